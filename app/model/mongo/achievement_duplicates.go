@@ -0,0 +1,216 @@
+package model
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	DuplicateReasonCertificationNumber = "certification_number"
+	DuplicateReasonAttachmentChecksum  = "attachment_checksum"
+	DuplicateReasonCompetitionEvent    = "competition_event"
+	DuplicateReasonPublication         = "publication"
+	DuplicateReasonSimilarTitle        = "similar_title"
+)
+
+// DuplicateScoreThreshold adalah skor minimal agar dua prestasi dianggap duplikat.
+const DuplicateScoreThreshold = 0.8
+
+// DuplicateMatch adalah prestasi lain yang mirip dengan prestasi yang diperiksa.
+type DuplicateMatch struct {
+	AchievementID string   `json:"achievementId"`
+	StudentID     string   `json:"studentId"`
+	Title         string   `json:"title"`
+	Score         float64  `json:"score"`
+	Reasons       []string `json:"reasons"`
+}
+
+type DuplicateClusterItem struct {
+	AchievementID   string    `json:"achievementId"`
+	StudentID       string    `json:"studentId"`
+	AchievementType string    `json:"achievementType"`
+	Title           string    `json:"title"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// DuplicateCluster adalah kumpulan prestasi yang saling terhubung oleh pasangan duplikat.
+type DuplicateCluster struct {
+	Score        float64                `json:"score"`
+	Reasons      []string               `json:"reasons"`
+	Achievements []DuplicateClusterItem `json:"achievements"`
+}
+
+type DuplicateReport struct {
+	Scanned  int                `json:"scanned"`
+	Clusters []DuplicateCluster `json:"clusters"`
+}
+
+type GetDuplicateReportResponse struct {
+	Status string          `json:"status"`
+	Data   DuplicateReport `json:"data"`
+}
+
+// CompareAchievements menilai kemiripan dua prestasi dengan tipe yang sama. Nomor sertifikat atau
+// checksum lampiran yang sama dianggap identik; nama kompetisi dengan tanggal yang sama dan
+// publikasi dengan ISSN yang sama serta judul mirip hampir pasti duplikat; selain itu skor adalah
+// kemiripan judul.
+func CompareAchievements(a Achievement, b Achievement) (float64, []string) {
+	if a.AchievementType != b.AchievementType {
+		return 0, nil
+	}
+
+	score := 0.0
+	reasons := []string{}
+	addReason := func(reason string, reasonScore float64) {
+		reasons = append(reasons, reason)
+		if reasonScore > score {
+			score = reasonScore
+		}
+	}
+
+	titleSimilarity := TitleSimilarity(a.Title, b.Title)
+
+	if sameNormalized(a.Details.CertificationNumber, b.Details.CertificationNumber) {
+		addReason(DuplicateReasonCertificationNumber, 1)
+	}
+	if sharesAttachmentChecksum(a.Attachments, b.Attachments) {
+		addReason(DuplicateReasonAttachmentChecksum, 1)
+	}
+	if sameNormalized(a.Details.CompetitionName, b.Details.CompetitionName) &&
+		a.Details.EventDate != nil && b.Details.EventDate != nil && sameDay(*a.Details.EventDate, *b.Details.EventDate) {
+		addReason(DuplicateReasonCompetitionEvent, 0.9)
+	}
+	if sameNormalized(a.Details.ISSN, b.Details.ISSN) {
+		publicationTitleSimilarity := titleSimilarity
+		if a.Details.PublicationTitle != nil && b.Details.PublicationTitle != nil {
+			publicationTitleSimilarity = TitleSimilarity(*a.Details.PublicationTitle, *b.Details.PublicationTitle)
+		}
+		if publicationTitleSimilarity >= 0.6 {
+			addReason(DuplicateReasonPublication, 0.95)
+		}
+	}
+	if titleSimilarity >= DuplicateScoreThreshold {
+		addReason(DuplicateReasonSimilarTitle, titleSimilarity)
+	}
+
+	return score, reasons
+}
+
+// TitleSimilarity mengembalikan koefisien Dice trigram dua judul setelah dinormalisasi (0-1).
+func TitleSimilarity(a string, b string) float64 {
+	a, b = NormalizeDuplicateText(a), NormalizeDuplicateText(b)
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	trigramsA, trigramsB := trigrams(a), trigrams(b)
+	shared := 0
+	for trigram := range trigramsA {
+		if trigramsB[trigram] {
+			shared++
+		}
+	}
+
+	return 2 * float64(shared) / float64(len(trigramsA)+len(trigramsB))
+}
+
+// NormalizeDuplicateText mengubah teks menjadi huruf kecil dan hanya menyisakan huruf dan angka
+// yang dipisahkan satu spasi.
+func NormalizeDuplicateText(text string) string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// DuplicateTitleTokens mengembalikan kata unik minimal 4 huruf dari judul untuk mencari kandidat
+// judul yang mirip.
+func DuplicateTitleTokens(title string) []string {
+	seen := make(map[string]bool)
+	tokens := []string{}
+	for _, token := range strings.Fields(NormalizeDuplicateText(title)) {
+		if len([]rune(token)) >= 4 && !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+	sort.Strings(tokens)
+	return tokens
+}
+
+func trigrams(text string) map[string]bool {
+	runes := []rune("  " + text + " ")
+	result := make(map[string]bool, len(runes))
+	for i := 0; i+3 <= len(runes); i++ {
+		result[string(runes[i:i+3])] = true
+	}
+	return result
+}
+
+func sameNormalized(a *string, b *string) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	normalizedA := NormalizeDuplicateText(*a)
+	return normalizedA != "" && normalizedA == NormalizeDuplicateText(*b)
+}
+
+func sharesAttachmentChecksum(a []Attachment, b []Attachment) bool {
+	checksums := make(map[string]bool, len(a))
+	for _, attachment := range a {
+		if attachment.Checksum != "" {
+			checksums[attachment.Checksum] = true
+		}
+	}
+	for _, attachment := range b {
+		if attachment.Checksum != "" && checksums[attachment.Checksum] {
+			return true
+		}
+	}
+	return false
+}
+
+func sameDay(a time.Time, b time.Time) bool {
+	a, b = a.UTC(), b.UTC()
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
+
+// DuplicateClusterItemFromAchievement meringkas prestasi untuk laporan duplikat.
+func DuplicateClusterItemFromAchievement(achievement Achievement) DuplicateClusterItem {
+	return DuplicateClusterItem{
+		AchievementID:   achievement.ID.Hex(),
+		StudentID:       achievement.StudentID,
+		AchievementType: achievement.AchievementType,
+		Title:           achievement.Title,
+		CreatedAt:       achievement.CreatedAt,
+	}
+}
+
+// DuplicateMatchFromAchievement membuat DuplicateMatch untuk prestasi candidate.
+func DuplicateMatchFromAchievement(candidate Achievement, score float64, reasons []string) DuplicateMatch {
+	return DuplicateMatch{
+		AchievementID: candidate.ID.Hex(),
+		StudentID:     candidate.StudentID,
+		Title:         candidate.Title,
+		Score:         score,
+		Reasons:       reasons,
+	}
+}
+
+// DuplicateCandidateFilter berisi nilai yang dipakai untuk mencari kandidat duplikat di MongoDB.
+type DuplicateCandidateFilter struct {
+	ExcludeID           primitive.ObjectID
+	AchievementType     string
+	Title               string
+	CertificationNumber *string
+	CompetitionName     *string
+	ISSN                *string
+	Checksums           []string
+}
@@ -0,0 +1,136 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	AchievementExportFormatCSV  = "csv"
+	AchievementExportFormatXLSX = "xlsx"
+	AchievementExportFormatJSON = "json"
+)
+
+func IsValidAchievementExportFormat(format string) bool {
+	switch format {
+	case AchievementExportFormatCSV, AchievementExportFormatXLSX, AchievementExportFormatJSON:
+		return true
+	}
+	return false
+}
+
+// AchievementExportColumns adalah header export CSV/XLSX, berurutan sesuai AchievementExportRow.Cells.
+// Nama kolom details mengikuti kolom import prestasi.
+var AchievementExportColumns = []string{
+	"achievementId", "nim", "studentName", "programStudy", "achievementType", "title", "description",
+	"status", "points", "tags",
+	"competitionName", "competitionLevel", "rank", "medalType",
+	"publicationType", "publicationTitle", "authors", "publisher", "issn",
+	"organizationName", "position", "periodStart", "periodEnd",
+	"certificationName", "issuedBy", "certificationNumber", "validUntil",
+	"eventDate", "location", "organizer", "score", "attachments",
+	"submittedAt", "verifiedAt", "verifiedBy", "rejectionNote", "createdAt", "updatedAt",
+}
+
+// AchievementExportRow adalah satu prestasi pada export: data dari MongoDB digabung dengan status
+// reference serta identitas mahasiswa pemilik dari PostgreSQL. VerifiedBy berisi nama verifikator.
+type AchievementExportRow struct {
+	AchievementID   string             `json:"achievementId"`
+	StudentNumber   string             `json:"nim"`
+	StudentName     string             `json:"studentName"`
+	ProgramStudy    string             `json:"programStudy"`
+	AchievementType string             `json:"achievementType"`
+	Title           string             `json:"title"`
+	Description     string             `json:"description"`
+	Status          string             `json:"status"`
+	Points          int                `json:"points"`
+	Tags            []string           `json:"tags"`
+	Details         AchievementDetails `json:"details"`
+	Attachments     []Attachment       `json:"attachments"`
+	SubmittedAt     *time.Time         `json:"submittedAt"`
+	VerifiedAt      *time.Time         `json:"verifiedAt"`
+	VerifiedBy      string             `json:"verifiedBy,omitempty"`
+	RejectionNote   *string            `json:"rejectionNote"`
+	CreatedAt       time.Time          `json:"createdAt"`
+	UpdatedAt       time.Time          `json:"updatedAt"`
+}
+
+// Cells mengembalikan nilai kolom sesuai AchievementExportColumns. Angka tetap bertipe numerik
+// agar terbaca sebagai angka di XLSX; nilai kosong berupa string kosong.
+func (r AchievementExportRow) Cells() []interface{} {
+	details := r.Details
+	text := func(value *string) interface{} {
+		if value == nil {
+			return ""
+		}
+		return *value
+	}
+	date := func(value *time.Time) interface{} {
+		if value == nil || value.IsZero() {
+			return ""
+		}
+		return value.Format("2006-01-02")
+	}
+	timestamp := func(value *time.Time) interface{} {
+		if value == nil || value.IsZero() {
+			return ""
+		}
+		return value.Format(time.RFC3339)
+	}
+
+	var rank, score interface{} = "", ""
+	if details.Rank != nil {
+		rank = *details.Rank
+	}
+	if details.Score != nil {
+		score = *details.Score
+	}
+	var periodStart, periodEnd interface{} = "", ""
+	if details.Period != nil {
+		periodStart = date(&details.Period.Start)
+		periodEnd = date(&details.Period.End)
+	}
+
+	attachments := make([]string, 0, len(r.Attachments))
+	for _, attachment := range r.Attachments {
+		attachments = append(attachments, attachment.FileURL)
+	}
+
+	return []interface{}{
+		r.AchievementID, r.StudentNumber, r.StudentName, r.ProgramStudy, r.AchievementType, r.Title, r.Description,
+		r.Status, r.Points, strings.Join(r.Tags, "; "),
+		text(details.CompetitionName), text(details.CompetitionLevel), rank, text(details.MedalType),
+		text(details.PublicationType), text(details.PublicationTitle), strings.Join(details.Authors, "; "), text(details.Publisher), text(details.ISSN),
+		text(details.OrganizationName), text(details.Position), periodStart, periodEnd,
+		text(details.CertificationName), text(details.IssuedBy), text(details.CertificationNumber), date(details.ValidUntil),
+		date(details.EventDate), text(details.Location), text(details.Organizer), score, strings.Join(attachments, "; "),
+		timestamp(r.SubmittedAt), timestamp(r.VerifiedAt), r.VerifiedBy, text(r.RejectionNote),
+		timestamp(&r.CreatedAt), timestamp(&r.UpdatedAt),
+	}
+}
+
+// Values mengembalikan Cells sebagai string untuk baris CSV. Teks yang diawali karakter formula
+// spreadsheet diberi awalan ' agar tidak dieksekusi saat file dibuka di Excel; angka tidak diubah.
+func (r AchievementExportRow) Values() []string {
+	cells := r.Cells()
+	values := make([]string, len(cells))
+	for i, cell := range cells {
+		switch value := cell.(type) {
+		case string:
+			values[i] = neutralizeCSVFormula(value)
+		case int:
+			values[i] = strconv.Itoa(value)
+		case float64:
+			values[i] = strconv.FormatFloat(value, 'f', -1, 64)
+		}
+	}
+	return values
+}
+
+func neutralizeCSVFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,259 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	AchievementImportStatusValid   = "valid"
+	AchievementImportStatusCreated = "created"
+	AchievementImportStatusFailed  = "failed"
+)
+
+// MaxAchievementImportRows membatasi jumlah baris data dalam satu file import.
+const MaxAchievementImportRows = 5000
+
+// AchievementImportColumns adalah kolom yang dikenali pada file import. Kolom nim wajib ada;
+// kolom details boleh ditulis dengan atau tanpa awalan "details.". Kolom tags dan authors
+// dipisahkan titik koma, tanggal memakai format YYYY-MM-DD.
+var AchievementImportColumns = []string{
+	"nim", "achievementType", "title", "description", "tags",
+	"competitionName", "competitionLevel", "rank", "medalType",
+	"publicationType", "publicationTitle", "authors", "publisher", "issn",
+	"organizationName", "position", "periodStart", "periodEnd",
+	"certificationName", "issuedBy", "certificationNumber", "validUntil",
+	"eventDate", "location", "organizer", "score",
+}
+
+// AchievementImportOptions mengatur proses import. InitialStatus adalah draft atau verified dan
+// ImportedBy adalah user yang dicatat sebagai pembuat (dan verifikator) prestasi.
+type AchievementImportOptions struct {
+	DryRun        bool
+	InitialStatus string
+	ImportedBy    string
+}
+
+// AchievementImportRow adalah satu baris data file import. Row adalah nomor baris pada file
+// (baris header adalah 1) dan Errors berisi kesalahan format sel.
+type AchievementImportRow struct {
+	Row           int
+	StudentNumber string
+	Request       CreateAchievementRequest
+	Errors        []FieldError
+}
+
+type AchievementImportRowResult struct {
+	Row             int              `json:"row"`
+	StudentNumber   string           `json:"nim"`
+	AchievementType string           `json:"achievementType"`
+	Title           string           `json:"title"`
+	Status          string           `json:"status"`
+	Points          *int             `json:"points,omitempty"`
+	AchievementID   string           `json:"achievementId,omitempty"`
+	Duplicates      []DuplicateMatch `json:"duplicates,omitempty"`
+	Errors          []FieldError     `json:"errors,omitempty"`
+}
+
+type AchievementImportReport struct {
+	DryRun        bool                         `json:"dryRun"`
+	InitialStatus string                       `json:"initialStatus"`
+	Total         int                          `json:"total"`
+	Valid         int                          `json:"valid"`
+	Created       int                          `json:"created"`
+	Failed        int                          `json:"failed"`
+	Rows          []AchievementImportRowResult `json:"rows"`
+}
+
+type ImportAchievementsResponse struct {
+	Status string                  `json:"status"`
+	Data   AchievementImportReport `json:"data"`
+}
+
+// ParseAchievementImportRecords memetakan baris CSV/XLSX ke CreateAchievementRequest. Baris
+// pertama adalah header. Kesalahan header dikembalikan sebagai error, sedangkan kesalahan sel
+// dicatat per baris agar baris lain tetap dapat diproses. Baris kosong dilewati.
+func ParseAchievementImportRecords(records [][]string) ([]AchievementImportRow, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("file kosong, baris header wajib ada")
+	}
+
+	known := make(map[string]string, len(AchievementImportColumns))
+	for _, column := range AchievementImportColumns {
+		known[strings.ToLower(column)] = column
+	}
+
+	columns := make([]string, len(records[0]))
+	seen := make(map[string]bool)
+	for i, header := range records[0] {
+		name := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(header), "details."))
+		if name == "" {
+			continue
+		}
+		column, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("kolom %q tidak dikenali", header)
+		}
+		if seen[column] {
+			return nil, fmt.Errorf("kolom %q ditulis lebih dari sekali", header)
+		}
+		seen[column] = true
+		columns[i] = column
+	}
+	if !seen["nim"] {
+		return nil, fmt.Errorf("kolom nim wajib ada")
+	}
+
+	rows := []AchievementImportRow{}
+	for i, record := range records[1:] {
+		if isBlankRecord(record) {
+			continue
+		}
+		if len(rows) == MaxAchievementImportRows {
+			return nil, fmt.Errorf("jumlah baris melebihi batas %d", MaxAchievementImportRows)
+		}
+
+		row := AchievementImportRow{Row: i + 2, Errors: []FieldError{}}
+		for j, value := range record {
+			if j >= len(columns) || columns[j] == "" {
+				continue
+			}
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			if err := row.setColumn(columns[j], value); err != nil {
+				row.Errors = append(row.Errors, FieldError{Field: columns[j], Message: err.Error()})
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func (row *AchievementImportRow) setColumn(column string, value string) error {
+	req := &row.Request
+	details := &req.Details
+
+	switch column {
+	case "nim":
+		row.StudentNumber = value
+	case "achievementType":
+		req.AchievementType = strings.ToLower(value)
+	case "title":
+		req.Title = value
+	case "description":
+		req.Description = value
+	case "tags":
+		req.Tags = splitImportList(value)
+	case "competitionName":
+		details.CompetitionName = &value
+	case "competitionLevel":
+		level := strings.ToLower(value)
+		details.CompetitionLevel = &level
+	case "rank":
+		rank, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.New("Peringkat harus berupa bilangan bulat.")
+		}
+		details.Rank = &rank
+	case "medalType":
+		details.MedalType = &value
+	case "publicationType":
+		publicationType := strings.ToLower(value)
+		details.PublicationType = &publicationType
+	case "publicationTitle":
+		details.PublicationTitle = &value
+	case "authors":
+		details.Authors = splitImportList(value)
+	case "publisher":
+		details.Publisher = &value
+	case "issn":
+		details.ISSN = &value
+	case "organizationName":
+		details.OrganizationName = &value
+	case "position":
+		details.Position = &value
+	case "periodStart", "periodEnd":
+		date, err := parseImportDate(value)
+		if err != nil {
+			return err
+		}
+		if details.Period == nil {
+			details.Period = &Period{}
+		}
+		if column == "periodStart" {
+			details.Period.Start = date
+		} else {
+			details.Period.End = date
+		}
+	case "certificationName":
+		details.CertificationName = &value
+	case "issuedBy":
+		details.IssuedBy = &value
+	case "certificationNumber":
+		details.CertificationNumber = &value
+	case "validUntil":
+		date, err := parseImportDate(value)
+		if err != nil {
+			return err
+		}
+		details.ValidUntil = &date
+	case "eventDate":
+		date, err := parseImportDate(value)
+		if err != nil {
+			return err
+		}
+		details.EventDate = &date
+	case "location":
+		details.Location = &value
+	case "organizer":
+		details.Organizer = &value
+	case "score":
+		score, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return errors.New("Skor harus berupa angka.")
+		}
+		details.Score = &score
+	}
+	return nil
+}
+
+// parseImportDate menerima YYYY-MM-DD, RFC3339, atau nomor seri tanggal Excel.
+func parseImportDate(value string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	if serial, err := strconv.ParseFloat(value, 64); err == nil && serial > 0 {
+		excelEpoch := time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+		days := math.Floor(serial)
+		return excelEpoch.AddDate(0, 0, int(days)), nil
+	}
+	return time.Time{}, errors.New("Tanggal tidak valid. Gunakan format YYYY-MM-DD.")
+}
+
+func splitImportList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
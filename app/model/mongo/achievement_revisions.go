@@ -0,0 +1,182 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	RevisionChangeAdded   = "added"
+	RevisionChangeRemoved = "removed"
+	RevisionChangeChanged = "changed"
+)
+
+// AchievementSnapshot adalah isi prestasi yang dapat diubah mahasiswa pada satu revisi.
+type AchievementSnapshot struct {
+	AchievementType    string             `bson:"achievementType" json:"achievementType"`
+	Title              string             `bson:"title" json:"title"`
+	Description        string             `bson:"description" json:"description"`
+	Details            AchievementDetails `bson:"details" json:"details"`
+	Attachments        []Attachment       `bson:"attachments,omitempty" json:"attachments,omitempty"`
+	Tags               []string           `bson:"tags,omitempty" json:"tags,omitempty"`
+	Points             int                `bson:"points" json:"points"`
+	PointRubricVersion int                `bson:"pointRubricVersion,omitempty" json:"pointRubricVersion,omitempty"`
+}
+
+// AchievementRevision adalah salinan isi prestasi setelah satu kali penulisan (create atau update).
+// Revisi tidak pernah diubah; PreviousStatus berisi status reference tepat sebelum penulisan,
+// sehingga revisi dengan PreviousStatus "rejected" adalah perbaikan setelah penolakan.
+type AchievementRevision struct {
+	ID             primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	AchievementID  primitive.ObjectID  `bson:"achievementId" json:"achievementId"`
+	Revision       int                 `bson:"revision" json:"revision"`
+	PreviousStatus string              `bson:"previousStatus,omitempty" json:"previousStatus,omitempty"`
+	EditedBy       string              `bson:"editedBy,omitempty" json:"editedBy,omitempty"`
+	Snapshot       AchievementSnapshot `bson:"snapshot" json:"snapshot"`
+	CreatedAt      time.Time           `bson:"createdAt" json:"createdAt"`
+}
+
+type AchievementRevisionChange struct {
+	Field string      `json:"field"`
+	Type  string      `json:"type"`
+	From  interface{} `json:"from,omitempty"`
+	To    interface{} `json:"to,omitempty"`
+}
+
+type AchievementRevisionDiff struct {
+	AchievementID string                      `json:"achievementId"`
+	From          AchievementRevision         `json:"from"`
+	To            AchievementRevision         `json:"to"`
+	Changes       []AchievementRevisionChange `json:"changes"`
+}
+
+type GetAchievementRevisionsResponse struct {
+	Status string                `json:"status"`
+	Data   []AchievementRevision `json:"data"`
+}
+
+type GetAchievementRevisionDiffResponse struct {
+	Status string                  `json:"status"`
+	Data   AchievementRevisionDiff `json:"data"`
+}
+
+func NewAchievementSnapshot(achievement Achievement) AchievementSnapshot {
+	return AchievementSnapshot{
+		AchievementType:    achievement.AchievementType,
+		Title:              achievement.Title,
+		Description:        achievement.Description,
+		Details:            achievement.Details,
+		Attachments:        achievement.Attachments,
+		Tags:               achievement.Tags,
+		Points:             achievement.Points,
+		PointRubricVersion: achievement.PointRubricVersion,
+	}
+}
+
+// ApplyAchievementUpdate mengembalikan isi prestasi setelah req diterapkan dengan aturan yang sama
+// seperti repository UpdateAchievement: field kosong atau nil tidak mengubah nilai lama.
+func ApplyAchievementUpdate(achievement Achievement, req UpdateAchievementRequest) Achievement {
+	if req.AchievementType != "" {
+		achievement.AchievementType = req.AchievementType
+	}
+	if req.Title != "" {
+		achievement.Title = req.Title
+	}
+	if req.Description != "" {
+		achievement.Description = req.Description
+	}
+	if req.Details != nil {
+		achievement.Details = *req.Details
+	}
+	if req.Attachments != nil {
+		achievement.Attachments = req.Attachments
+	}
+	if req.Tags != nil {
+		achievement.Tags = req.Tags
+	}
+	if req.Points != nil {
+		achievement.Points = *req.Points
+	}
+	if req.PointRubricVersion != nil {
+		achievement.PointRubricVersion = *req.PointRubricVersion
+	}
+	return achievement
+}
+
+// DiffAchievementSnapshots membandingkan dua snapshot per field. Field di dalam details ditulis
+// dengan path bertitik (misalnya details.rank); array seperti tags dan attachments dibandingkan utuh.
+func DiffAchievementSnapshots(from AchievementSnapshot, to AchievementSnapshot) ([]AchievementRevisionChange, error) {
+	fromFields, err := flattenSnapshot(from)
+	if err != nil {
+		return nil, err
+	}
+	toFields, err := flattenSnapshot(to)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(fromFields)+len(toFields))
+	for field := range fromFields {
+		fields = append(fields, field)
+	}
+	for field := range toFields {
+		if _, exists := fromFields[field]; !exists {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	changes := []AchievementRevisionChange{}
+	for _, field := range fields {
+		oldValue, inFrom := fromFields[field]
+		newValue, inTo := toFields[field]
+
+		switch {
+		case !inFrom:
+			changes = append(changes, AchievementRevisionChange{Field: field, Type: RevisionChangeAdded, To: newValue})
+		case !inTo:
+			changes = append(changes, AchievementRevisionChange{Field: field, Type: RevisionChangeRemoved, From: oldValue})
+		case !reflect.DeepEqual(oldValue, newValue):
+			changes = append(changes, AchievementRevisionChange{Field: field, Type: RevisionChangeChanged, From: oldValue, To: newValue})
+		}
+	}
+
+	return changes, nil
+}
+
+// flattenSnapshot mengubah snapshot menjadi map field JSON. Representasi JSON dipakai agar nama
+// field dan format nilai pada diff sama dengan response API lainnya.
+func flattenSnapshot(snapshot AchievementSnapshot) (map[string]interface{}, error) {
+	raw, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	var document map[string]interface{}
+	if err := json.Unmarshal(raw, &document); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]interface{})
+	flattenFields("", document, fields)
+	return fields, nil
+}
+
+func flattenFields(prefix string, document map[string]interface{}, fields map[string]interface{}) {
+	for key, value := range document {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		if nested, ok := value.(map[string]interface{}); ok {
+			flattenFields(path, nested, fields)
+			continue
+		}
+		fields[path] = value
+	}
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -28,6 +30,111 @@ const (
 	PublicationTypeBook       = "book"
 )
 
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func IsValidAchievementType(achievementType string) bool {
+	switch achievementType {
+	case AchievementTypeAcademic, AchievementTypeCompetition, AchievementTypeOrganization,
+		AchievementTypePublication, AchievementTypeCertification, AchievementTypeOther:
+		return true
+	}
+	return false
+}
+
+func IsValidCompetitionLevel(level string) bool {
+	switch level {
+	case CompetitionLevelInternational, CompetitionLevelNational, CompetitionLevelRegional, CompetitionLevelLocal:
+		return true
+	}
+	return false
+}
+
+func IsValidPublicationType(publicationType string) bool {
+	switch publicationType {
+	case PublicationTypeJournal, PublicationTypeConference, PublicationTypeBook:
+		return true
+	}
+	return false
+}
+
+// ValidateCreateAchievementRequest memeriksa field wajib prestasi baru beserta details-nya.
+func ValidateCreateAchievementRequest(req CreateAchievementRequest) []FieldError {
+	errors := []FieldError{}
+	if req.AchievementType == "" {
+		errors = append(errors, FieldError{Field: "achievementType", Message: "Achievement type wajib diisi."})
+	} else if !IsValidAchievementType(req.AchievementType) {
+		errors = append(errors, FieldError{Field: "achievementType", Message: "Achievement type tidak valid. Gunakan: academic, competition, organization, publication, certification, atau other."})
+	}
+	if strings.TrimSpace(req.Title) == "" {
+		errors = append(errors, FieldError{Field: "title", Message: "Title wajib diisi."})
+	}
+	if strings.TrimSpace(req.Description) == "" {
+		errors = append(errors, FieldError{Field: "description", Message: "Description wajib diisi."})
+	}
+	return append(errors, ValidateAchievementDetails(req.AchievementType, req.Details)...)
+}
+
+// ValidateAchievementDetails memeriksa field details yang wajib untuk tipe prestasi tertentu.
+// Tipe academic dan other tidak memiliki field wajib.
+func ValidateAchievementDetails(achievementType string, details AchievementDetails) []FieldError {
+	errors := []FieldError{}
+	isBlank := func(value *string) bool {
+		return value == nil || strings.TrimSpace(*value) == ""
+	}
+
+	switch achievementType {
+	case AchievementTypeCompetition:
+		if isBlank(details.CompetitionName) {
+			errors = append(errors, FieldError{Field: "details.competitionName", Message: "Nama kompetisi wajib diisi."})
+		}
+		if isBlank(details.CompetitionLevel) {
+			errors = append(errors, FieldError{Field: "details.competitionLevel", Message: "Tingkat kompetisi wajib diisi."})
+		} else if !IsValidCompetitionLevel(*details.CompetitionLevel) {
+			errors = append(errors, FieldError{Field: "details.competitionLevel", Message: "Tingkat kompetisi tidak valid. Gunakan: international, national, regional, atau local."})
+		}
+		if details.Rank != nil && *details.Rank < 1 {
+			errors = append(errors, FieldError{Field: "details.rank", Message: "Peringkat minimal 1."})
+		}
+	case AchievementTypePublication:
+		if isBlank(details.PublicationType) {
+			errors = append(errors, FieldError{Field: "details.publicationType", Message: "Tipe publikasi wajib diisi."})
+		} else if !IsValidPublicationType(*details.PublicationType) {
+			errors = append(errors, FieldError{Field: "details.publicationType", Message: "Tipe publikasi tidak valid. Gunakan: journal, conference, atau book."})
+		}
+		if len(details.Authors) == 0 {
+			errors = append(errors, FieldError{Field: "details.authors", Message: "Minimal satu penulis wajib diisi."})
+		}
+		for i, author := range details.Authors {
+			if strings.TrimSpace(author) == "" {
+				errors = append(errors, FieldError{Field: fmt.Sprintf("details.authors[%d]", i), Message: "Nama penulis tidak boleh kosong."})
+			}
+		}
+	case AchievementTypeOrganization:
+		if details.Period == nil {
+			errors = append(errors, FieldError{Field: "details.period", Message: "Periode organisasi wajib diisi."})
+			break
+		}
+		if details.Period.Start.IsZero() {
+			errors = append(errors, FieldError{Field: "details.period.start", Message: "Tanggal mulai periode wajib diisi."})
+		}
+		if details.Period.End.IsZero() {
+			errors = append(errors, FieldError{Field: "details.period.end", Message: "Tanggal selesai periode wajib diisi."})
+		}
+		if !details.Period.Start.IsZero() && !details.Period.End.IsZero() && !details.Period.Start.Before(details.Period.End) {
+			errors = append(errors, FieldError{Field: "details.period", Message: "Tanggal mulai periode harus sebelum tanggal selesai."})
+		}
+	case AchievementTypeCertification:
+		if isBlank(details.IssuedBy) {
+			errors = append(errors, FieldError{Field: "details.issuedBy", Message: "Penerbit sertifikasi wajib diisi."})
+		}
+	}
+
+	return errors
+}
+
 type Period struct {
 	Start time.Time `bson:"start" json:"start"`
 	End   time.Time `bson:"end" json:"end"`
@@ -37,6 +144,8 @@ type Attachment struct {
 	FileName    string    `bson:"fileName" json:"fileName"`
 	FileURL     string    `bson:"fileUrl" json:"fileUrl"`
 	FileType    string    `bson:"fileType" json:"fileType"`
+	// Checksum adalah SHA-256 isi file yang diunggah ke /uploads, dihitung server.
+	Checksum    string    `bson:"checksum,omitempty" json:"checksum,omitempty"`
 	UploadedAt  time.Time `bson:"uploadedAt" json:"uploadedAt"`
 }
 
@@ -74,6 +183,7 @@ type Achievement struct {
 	Attachments     []Attachment       `bson:"attachments,omitempty" json:"attachments,omitempty"`
 	Tags            []string           `bson:"tags,omitempty" json:"tags,omitempty"`
 	Points          int                `bson:"points" json:"points"`
+	PointRubricVersion int             `bson:"pointRubricVersion,omitempty" json:"pointRubricVersion,omitempty"`
 	DeletedAt       *time.Time         `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
 	CreatedAt       time.Time          `bson:"createdAt" json:"createdAt"`
 	UpdatedAt       time.Time          `bson:"updatedAt" json:"updatedAt"`
@@ -87,7 +197,8 @@ type CreateAchievementRequest struct {
 	Details         AchievementDetails `bson:"details" json:"details"`
 	Attachments     []Attachment       `bson:"attachments,omitempty" json:"attachments,omitempty"`
 	Tags            []string           `bson:"tags,omitempty" json:"tags,omitempty"`
-	Points          int                `bson:"points" json:"points" validate:"required"`
+	// Points dihitung server dari rubrik poin yang aktif, bukan dari input client.
+	Points          int                `bson:"points" json:"-"`
 }
 
 type UpdateAchievementRequest struct {
@@ -97,7 +208,28 @@ type UpdateAchievementRequest struct {
 	Details         *AchievementDetails `bson:"details,omitempty" json:"details,omitempty"`
 	Attachments     []Attachment       `bson:"attachments,omitempty" json:"attachments,omitempty"`
 	Tags            []string           `bson:"tags,omitempty" json:"tags,omitempty"`
-	Points          *int               `bson:"points,omitempty" json:"points,omitempty"`
+	// Points dan PointRubricVersion diisi server dari rubrik poin yang aktif.
+	Points             *int            `bson:"points,omitempty" json:"-"`
+	PointRubricVersion *int            `bson:"pointRubricVersion,omitempty" json:"-"`
+}
+
+type AchievementFilter struct {
+	IDs             []string
+	AchievementType string
+	Tags            []string
+	MinPoints       *int
+	MaxPoints       *int
+	DateFrom        *time.Time
+	DateTo          *time.Time
+	SortField       string
+	SortOrder       int
+	Limit           int
+	Offset          int
+}
+
+type AchievementSearchResult struct {
+	Achievement `bson:",inline"`
+	Score       float64 `bson:"score" json:"score"`
 }
 
 type GetAllAchievementsResponse struct {
@@ -111,8 +243,10 @@ type GetAchievementByIDResponse struct {
 }
 
 type CreateAchievementResponse struct {
-	Status string      `json:"status"`
-	Data   Achievement `json:"data"`
+	Status           string      `json:"status"`
+	Data             Achievement `json:"data"`
+	DuplicateCount   int         `json:"duplicate_count,omitempty"`
+	DuplicateReasons []string    `json:"duplicate_reasons,omitempty"`
 }
 
 type UpdateAchievementResponse struct {
@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+const MaxAchievementCommentLength = 5000
+
+// AchievementComment adalah satu komentar pada thread prestasi. AuthorName dan AuthorRole diisi
+// dari tabel users dan roles saat dibaca.
+type AchievementComment struct {
+	ID                     string    `json:"id"`
+	AchievementReferenceID string    `json:"achievement_reference_id"`
+	AuthorID               *string   `json:"author_id"`
+	AuthorName             *string   `json:"author_name"`
+	AuthorRole             *string   `json:"author_role"`
+	Body                   string    `json:"body"`
+	AttachmentName         *string   `json:"attachment_name"`
+	AttachmentURL          *string   `json:"attachment_url"`
+	CreatedAt              time.Time `json:"created_at"`
+	UpdatedAt              time.Time `json:"updated_at"`
+}
+
+// CreateAchievementCommentRequest juga dipakai untuk mengedit komentar. AttachmentURL merujuk
+// file yang sudah diunggah melalui endpoint upload prestasi.
+type CreateAchievementCommentRequest struct {
+	Body           string  `json:"body" validate:"required"`
+	AttachmentName *string `json:"attachment_name"`
+	AttachmentURL  *string `json:"attachment_url"`
+}
+
+type GetAchievementCommentsResponse struct {
+	Status string               `json:"status"`
+	Data   []AchievementComment `json:"data"`
+}
+
+type AchievementCommentResponse struct {
+	Status string             `json:"status"`
+	Data   AchievementComment `json:"data"`
+}
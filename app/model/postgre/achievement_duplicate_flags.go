@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// AchievementDuplicateFlag menandai prestasi lain yang mirip dengan prestasi ini. Reasons berisi
+// sinyal kemiripan (certification_number, attachment_checksum, competition_event, publication,
+// similar_title).
+type AchievementDuplicateFlag struct {
+	ID                        string     `json:"id"`
+	AchievementReferenceID    string     `json:"achievement_reference_id"`
+	MatchedMongoAchievementID string     `json:"matched_mongo_achievement_id"`
+	Score                     float64    `json:"score"`
+	Reasons                   []string   `json:"reasons"`
+	DismissedBy               *string    `json:"dismissed_by"`
+	DismissedAt               *time.Time `json:"dismissed_at"`
+	CreatedAt                 time.Time  `json:"created_at"`
+	UpdatedAt                 time.Time  `json:"updated_at"`
+}
+
+type SubmitAchievementResponse struct {
+	Status           string               `json:"status"`
+	Data             AchievementReference `json:"data"`
+	DuplicateCount   int                  `json:"duplicate_count,omitempty"`
+	DuplicateReasons []string             `json:"duplicate_reasons,omitempty"`
+}
+
+type GetAchievementDuplicateFlagsResponse struct {
+	Status string                     `json:"status"`
+	Data   []AchievementDuplicateFlag `json:"data"`
+}
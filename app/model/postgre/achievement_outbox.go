@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+const (
+	AchievementOutboxOperationCreate = "create"
+	AchievementOutboxOperationDelete = "delete"
+	AchievementOutboxOperationPoints = "points"
+
+	AchievementOutboxStatusPending     = "pending"
+	AchievementOutboxStatusCompleted   = "completed"
+	AchievementOutboxStatusCompensated = "compensated"
+	AchievementOutboxStatusFailed      = "failed"
+)
+
+// AchievementOutbox mencatat operasi lintas PostgreSQL dan MongoDB yang belum tentu selesai.
+// Entri pending yang melewati next_attempt_at diselesaikan atau dikompensasi oleh worker outbox.
+type AchievementOutbox struct {
+	ID                     string    `json:"id"`
+	Operation              string    `json:"operation"`
+	MongoAchievementID     string    `json:"mongo_achievement_id"`
+	AchievementReferenceID *string   `json:"achievement_reference_id"`
+	CreatedBy              *string   `json:"created_by"`
+	IdempotencyKey         *string   `json:"idempotency_key"`
+	Status                 string    `json:"status"`
+	Attempts               int       `json:"attempts"`
+	LastError              *string   `json:"last_error"`
+	NextAttemptAt          time.Time `json:"next_attempt_at"`
+	CreatedAt              time.Time `json:"created_at"`
+	UpdatedAt              time.Time `json:"updated_at"`
+}
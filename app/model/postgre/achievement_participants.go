@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+const (
+	ParticipantRoleLeader = "leader"
+	ParticipantRoleMember = "member"
+)
+
+const MaxAchievementParticipants = 30
+
+func IsValidParticipantRole(role string) bool {
+	return role == ParticipantRoleLeader || role == ParticipantRoleMember
+}
+
+// AchievementParticipant adalah anggota prestasi tim. Points berisi bagian poin peserta dan baru
+// terisi setelah prestasi diverifikasi; sebelum itu response menampilkan EstimatedPoints.
+type AchievementParticipant struct {
+	ID                     string    `json:"id"`
+	AchievementReferenceID string    `json:"achievement_reference_id"`
+	StudentID              string    `json:"student_id"`
+	StudentNumber          string    `json:"student_number"`
+	FullName               string    `json:"full_name"`
+	Role                   string    `json:"role"`
+	Points                 *int      `json:"points"`
+	EstimatedPoints        *int      `json:"estimated_points,omitempty"`
+	CreatedAt              time.Time `json:"created_at"`
+}
+
+type AchievementParticipantInput struct {
+	StudentID string `json:"student_id" validate:"required"`
+	Role      string `json:"role" validate:"required,oneof=leader member"`
+}
+
+// SetAchievementParticipantsRequest mengganti seluruh peserta prestasi. Daftar kosong mengubah
+// prestasi kembali menjadi prestasi individu.
+type SetAchievementParticipantsRequest struct {
+	Participants []AchievementParticipantInput `json:"participants"`
+}
+
+type GetAchievementParticipantsResponse struct {
+	Status string                   `json:"status"`
+	Data   []AchievementParticipant `json:"data"`
+}
@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+const (
+	// ReconcileIssueDocumentOrphan: dokumen MongoDB aktif tanpa reference di PostgreSQL.
+	ReconcileIssueDocumentOrphan = "document_orphan"
+	// ReconcileIssueReferenceOrphan: reference aktif yang dokumen MongoDB-nya tidak ada.
+	ReconcileIssueReferenceOrphan = "reference_orphan"
+	// ReconcileIssueStudentMismatch: studentId dokumen berbeda dengan student_id reference.
+	ReconcileIssueStudentMismatch = "student_mismatch"
+	// ReconcileIssueDeletedDocument: dokumen sudah di-soft delete tetapi reference belum deleted.
+	ReconcileIssueDeletedDocument = "deleted_document_active_reference"
+	// ReconcileIssueDeletedReference: reference sudah deleted tetapi dokumen belum di-soft delete.
+	ReconcileIssueDeletedReference = "deleted_reference_active_document"
+)
+
+var ReconcileIssueTypes = []string{
+	ReconcileIssueDocumentOrphan,
+	ReconcileIssueReferenceOrphan,
+	ReconcileIssueStudentMismatch,
+	ReconcileIssueDeletedDocument,
+	ReconcileIssueDeletedReference,
+}
+
+type ReconcileIssue struct {
+	Type                   string  `json:"type"`
+	MongoAchievementID     string  `json:"mongo_achievement_id"`
+	AchievementReferenceID *string `json:"achievement_reference_id,omitempty"`
+	ReferenceStatus        string  `json:"reference_status,omitempty"`
+	MongoStudentID         string  `json:"mongo_student_id,omitempty"`
+	PostgresStudentID      string  `json:"postgres_student_id,omitempty"`
+	Repair                 string  `json:"repair"`
+	Fixed                  bool    `json:"fixed"`
+	FixError               string  `json:"fix_error,omitempty"`
+}
+
+type ReconcileReport struct {
+	GeneratedAt       time.Time        `json:"generated_at"`
+	DryRun            bool             `json:"dry_run"`
+	ScannedDocuments  int              `json:"scanned_documents"`
+	ScannedReferences int              `json:"scanned_references"`
+	SkippedInFlight   int              `json:"skipped_in_flight"`
+	Summary           map[string]int   `json:"summary"`
+	Issues            []ReconcileIssue `json:"issues"`
+}
+
+// Unresolved menghitung masalah yang belum diperbaiki.
+func (r ReconcileReport) Unresolved() int {
+	count := 0
+	for _, issue := range r.Issues {
+		if !issue.Fixed {
+			count++
+		}
+	}
+	return count
+}
@@ -10,6 +10,28 @@ const (
 	AchievementStatusDeleted   = "deleted"
 )
 
+// achievementStatusTransitions adalah satu-satunya sumber aturan perpindahan status prestasi.
+var achievementStatusTransitions = map[string][]string{
+	AchievementStatusDraft:     {AchievementStatusSubmitted, AchievementStatusDeleted},
+	AchievementStatusSubmitted: {AchievementStatusVerified, AchievementStatusRejected},
+	AchievementStatusRejected:  {AchievementStatusDraft, AchievementStatusSubmitted, AchievementStatusDeleted},
+}
+
+func CanTransitionAchievementStatus(from, to string) bool {
+	for _, status := range achievementStatusTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAchievementEditable menandakan konten prestasi boleh diubah oleh pemiliknya.
+// Prestasi rejected yang diubah akan kembali ke draft dengan rejection note tetap tersimpan.
+func IsAchievementEditable(status string) bool {
+	return status == AchievementStatusDraft || CanTransitionAchievementStatus(status, AchievementStatusDraft)
+}
+
 type AchievementReference struct {
 	ID                 string     `json:"id"`
 	StudentID           string     `json:"student_id"`
@@ -23,6 +45,23 @@ type AchievementReference struct {
 	UpdatedAt          time.Time  `json:"updated_at"`
 }
 
+// AchievementExportReference adalah reference prestasi beserta identitas mahasiswa pemilik dan
+// nama verifikator, dipakai saat export.
+type AchievementExportReference struct {
+	AchievementReference
+	StudentNumber string
+	StudentName   string
+	ProgramStudy  string
+	VerifierName  string
+}
+
+type AchievementReferenceFilter struct {
+	ScopeStudentID string
+	AdvisorID      string
+	StudentID      string
+	Status         string
+}
+
 type CreateAchievementReferenceRequest struct {
 	StudentID           string `json:"student_id" validate:"required"`
 	MongoAchievementID  string `json:"mongo_achievement_id" validate:"required"`
@@ -39,6 +78,10 @@ type VerifyAchievementRequest struct {
 	RejectionNote string `json:"rejection_note"`
 }
 
+type RejectAchievementRequest struct {
+	RejectionNote string `json:"rejection_note" validate:"required"`
+}
+
 type GetAllAchievementReferencesResponse struct {
 	Status string                 `json:"status"`
 	Data   []AchievementReference `json:"data"`
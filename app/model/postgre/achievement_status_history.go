@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+type AchievementStatusHistory struct {
+	ID                     string    `json:"id"`
+	AchievementReferenceID string    `json:"achievement_reference_id"`
+	FromStatus             *string   `json:"from_status"`
+	ToStatus               string    `json:"to_status"`
+	ChangedBy              *string   `json:"changed_by"`
+	ChangedByName          *string   `json:"changed_by_name"`
+	Note                   *string   `json:"note"`
+	CreatedAt              time.Time `json:"created_at"`
+}
+
+type GetAchievementStatusHistoryResponse struct {
+	Status string                     `json:"status"`
+	Data   []AchievementStatusHistory `json:"data"`
+}
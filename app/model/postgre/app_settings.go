@@ -0,0 +1,17 @@
+package model
+
+const (
+	AppSettingEnvironment = "environment"
+
+	EnvironmentDevelopment = "development"
+	EnvironmentStaging     = "staging"
+	EnvironmentProduction  = "production"
+)
+
+func IsValidEnvironment(environment string) bool {
+	switch environment {
+	case EnvironmentDevelopment, EnvironmentStaging, EnvironmentProduction:
+		return true
+	}
+	return false
+}
@@ -21,9 +21,21 @@ type UpdateLecturerRequest struct {
 	Department string `json:"department"`
 }
 
+type LecturerFilter struct {
+	Search string
+	Limit  int
+	Offset int
+}
+
 type GetAllLecturersResponse struct {
-	Status string     `json:"status"`
-	Data   []Lecturer `json:"data"`
+	Status     string     `json:"status"`
+	Data       []Lecturer `json:"data"`
+	Pagination Pagination `json:"pagination"`
+}
+
+type GetLecturerAdviseesResponse struct {
+	Status string    `json:"status"`
+	Data   []Student `json:"data"`
 }
 
 type GetLecturerByIDResponse struct {
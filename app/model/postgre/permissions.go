@@ -1,5 +1,32 @@
 package model
 
+const (
+	PermissionUserManage = "user:manage"
+)
+
+// Scope akses prestasi dinyatakan dengan permission achievement:<aksi>:<scope>,
+// misalnya achievement:read:own atau achievement:verify:advisees.
+const (
+	AchievementActionRead   = "read"
+	AchievementActionWrite  = "write"
+	AchievementActionVerify = "verify"
+
+	AchievementScopeOwn      = "own"
+	AchievementScopeAdvisees = "advisees"
+	AchievementScopeAll      = "all"
+)
+
+// AchievementScopes diurutkan dari scope terluas ke tersempit.
+var AchievementScopes = []string{
+	AchievementScopeAll,
+	AchievementScopeAdvisees,
+	AchievementScopeOwn,
+}
+
+func AchievementScopePermission(action string, scope string) string {
+	return "achievement:" + action + ":" + scope
+}
+
 type Permission struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
@@ -45,3 +72,8 @@ type UpdatePermissionResponse struct {
 type DeletePermissionResponse struct {
 	Status string `json:"status"`
 }
+
+type GetRolePermissionsResponse struct {
+	Status string       `json:"status"`
+	Data   []Permission `json:"data"`
+}
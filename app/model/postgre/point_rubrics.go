@@ -0,0 +1,222 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	PointRubricStatusDraft     = "draft"
+	PointRubricStatusPublished = "published"
+	PointRubricStatusArchived  = "archived"
+)
+
+const (
+	TeamSplitModeFull     = "full"
+	TeamSplitModeEqual    = "equal"
+	TeamSplitModeWeighted = "weighted"
+)
+
+func IsValidTeamSplitMode(mode string) bool {
+	switch mode {
+	case TeamSplitModeFull, TeamSplitModeEqual, TeamSplitModeWeighted:
+		return true
+	}
+	return false
+}
+
+// PointRule memberi poin untuk prestasi dengan achievementType tertentu. Kriteria lain yang
+// diisi harus cocok seluruhnya; kriteria kosong berarti berlaku untuk semua nilai.
+type PointRule struct {
+	AchievementType  string `json:"achievement_type"`
+	CompetitionLevel string `json:"competition_level,omitempty"`
+	Rank             *int   `json:"rank,omitempty"`
+	MedalType        string `json:"medal_type,omitempty"`
+	PublicationType  string `json:"publication_type,omitempty"`
+	Position         string `json:"position,omitempty"`
+	Points           int    `json:"points"`
+}
+
+// TeamSplitRule menentukan pembagian poin prestasi tim. Rule tanpa AchievementType berlaku untuk
+// semua tipe yang tidak memiliki rule sendiri. Mode full memberi poin penuh ke setiap peserta,
+// equal membagi rata, dan weighted membagi sesuai LeaderWeight dan MemberWeight.
+type TeamSplitRule struct {
+	AchievementType string `json:"achievement_type,omitempty"`
+	Mode            string `json:"mode"`
+	LeaderWeight    int    `json:"leader_weight,omitempty"`
+	MemberWeight    int    `json:"member_weight,omitempty"`
+}
+
+type PointCriteria struct {
+	AchievementType  string
+	CompetitionLevel string
+	Rank             *int
+	MedalType        string
+	PublicationType  string
+	Position         string
+}
+
+func (r PointRule) specificity() int {
+	count := 0
+	for _, value := range []string{r.CompetitionLevel, r.MedalType, r.PublicationType, r.Position} {
+		if value != "" {
+			count++
+		}
+	}
+	if r.Rank != nil {
+		count++
+	}
+	return count
+}
+
+func (r PointRule) matches(criteria PointCriteria) bool {
+	if r.AchievementType != criteria.AchievementType {
+		return false
+	}
+	if r.CompetitionLevel != "" && !strings.EqualFold(r.CompetitionLevel, criteria.CompetitionLevel) {
+		return false
+	}
+	if r.Rank != nil && (criteria.Rank == nil || *r.Rank != *criteria.Rank) {
+		return false
+	}
+	if r.MedalType != "" && !strings.EqualFold(r.MedalType, criteria.MedalType) {
+		return false
+	}
+	if r.PublicationType != "" && !strings.EqualFold(r.PublicationType, criteria.PublicationType) {
+		return false
+	}
+	if r.Position != "" && !strings.EqualFold(r.Position, criteria.Position) {
+		return false
+	}
+	return true
+}
+
+type PointRubric struct {
+	ID             string          `json:"id"`
+	Version        int             `json:"version"`
+	Status         string          `json:"status"`
+	Rules          []PointRule     `json:"rules"`
+	TeamSplitRules []TeamSplitRule `json:"team_split_rules"`
+	CreatedBy      *string         `json:"created_by"`
+	PublishedAt    *time.Time      `json:"published_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// CalculatePoints mengembalikan poin dari rule paling spesifik yang cocok dengan criteria.
+// Jika beberapa rule sama spesifiknya, rule yang lebih awal dipakai. Tanpa rule yang cocok, poin 0.
+func (r PointRubric) CalculatePoints(criteria PointCriteria) int {
+	points := 0
+	best := -1
+	for _, rule := range r.Rules {
+		if !rule.matches(criteria) {
+			continue
+		}
+		if specificity := rule.specificity(); specificity > best {
+			best = specificity
+			points = rule.Points
+		}
+	}
+	return points
+}
+
+// SplitPoints membagi points ke peserta prestasi tim sesuai urutan roles. Sisa pembagian
+// diberikan ke leader (atau peserta pertama jika tidak ada leader) agar total tidak berkurang.
+// Jika bobot semua peserta 0 (misalnya tim tanpa member dengan leader_weight 0), poin dibagi rata.
+func (r PointRubric) SplitPoints(achievementType string, points int, roles []string) []int {
+	shares := make([]int, len(roles))
+	if len(roles) == 0 {
+		return shares
+	}
+
+	rule := TeamSplitRule{Mode: TeamSplitModeFull}
+	for _, candidate := range r.TeamSplitRules {
+		if candidate.AchievementType == achievementType {
+			rule = candidate
+			break
+		}
+		if candidate.AchievementType == "" {
+			rule = candidate
+		}
+	}
+
+	if rule.Mode == TeamSplitModeFull {
+		for i := range shares {
+			shares[i] = points
+		}
+		return shares
+	}
+
+	weights := make([]int, len(roles))
+	totalWeight := 0
+	remainderIndex := 0
+	for i, role := range roles {
+		weights[i] = 1
+		if rule.Mode == TeamSplitModeWeighted {
+			weights[i] = rule.MemberWeight
+			if role == ParticipantRoleLeader {
+				weights[i] = rule.LeaderWeight
+			}
+		}
+		if role == ParticipantRoleLeader {
+			remainderIndex = i
+		}
+		totalWeight += weights[i]
+	}
+	if totalWeight == 0 {
+		for i := range weights {
+			weights[i] = 1
+		}
+		totalWeight = len(weights)
+	}
+
+	distributed := 0
+	for i, weight := range weights {
+		shares[i] = points * weight / totalWeight
+		distributed += shares[i]
+	}
+	shares[remainderIndex] += points - distributed
+
+	return shares
+}
+
+type SavePointRubricRequest struct {
+	Rules          []PointRule     `json:"rules" validate:"required"`
+	TeamSplitRules []TeamSplitRule `json:"team_split_rules"`
+}
+
+type PointRubricPreviewItem struct {
+	AchievementID string `json:"achievement_id"`
+	StudentID     string `json:"student_id"`
+	Title         string `json:"title"`
+	Status        string `json:"status"`
+	CurrentPoints int    `json:"current_points"`
+	NewPoints     int    `json:"new_points"`
+}
+
+type PointRubricPreview struct {
+	DraftVersion int                      `json:"draft_version"`
+	Evaluated    int                      `json:"evaluated"`
+	Changed      int                      `json:"changed"`
+	Items        []PointRubricPreviewItem `json:"items"`
+}
+
+type GetAllPointRubricsResponse struct {
+	Status string        `json:"status"`
+	Data   []PointRubric `json:"data"`
+}
+
+type GetPointRubricResponse struct {
+	Status string      `json:"status"`
+	Data   PointRubric `json:"data"`
+}
+
+type PreviewPointRubricResponse struct {
+	Status     string             `json:"status"`
+	Data       PointRubricPreview `json:"data"`
+	Pagination Pagination         `json:"pagination"`
+}
+
+type DeletePointRubricResponse struct {
+	Status string `json:"status"`
+}
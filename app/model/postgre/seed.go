@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+const (
+	SeedFixtureMinimal  = "minimal"
+	SeedFixtureDemo     = "demo"
+	SeedFixtureLoadTest = "load-test"
+)
+
+var SeedFixtures = []string{SeedFixtureMinimal, SeedFixtureDemo, SeedFixtureLoadTest}
+
+// SeedOptions mengatur fixture seeding. Password kosong berarti memakai password bawaan fixture
+// (demo) atau password acak yang dicetak di hasil (minimal dan load-test). Jumlah data hanya
+// dipakai oleh fixture load-test.
+type SeedOptions struct {
+	Password               string
+	Lecturers              int
+	Students               int
+	AchievementsPerStudent int
+	RandomSeed             int64
+}
+
+type SeedCredential struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type SeedResult struct {
+	Fixture      string           `json:"fixture"`
+	Users        int              `json:"users"`
+	Lecturers    int              `json:"lecturers"`
+	Students     int              `json:"students"`
+	Achievements int              `json:"achievements"`
+	Credentials  []SeedCredential `json:"credentials"`
+}
+
+// SeedAchievementReference adalah reference prestasi hasil seeding beserta user yang tercatat
+// di riwayat status.
+type SeedAchievementReference struct {
+	StudentID          string
+	MongoAchievementID string
+	Status             string
+	RejectionNote      *string
+	StudentUserID      string
+	AdvisorUserID      *string
+	CreatedAt          time.Time
+}
@@ -0,0 +1,164 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	StudentImportStatusCreated   = "created"
+	StudentImportStatusUpdated   = "updated"
+	StudentImportStatusUnchanged = "unchanged"
+	StudentImportStatusFailed    = "failed"
+)
+
+// MaxStudentImportRows membatasi jumlah baris data dalam satu file import mahasiswa.
+const MaxStudentImportRows = 5000
+
+// StudentImportColumns adalah kolom yang dikenali pada file import mahasiswa. Kolom student_id
+// (NIM, boleh ditulis nim) wajib ada dan menjadi kunci upsert.
+var StudentImportColumns = []string{
+	"student_id", "full_name", "email", "username", "program_study", "academic_year", "advisor_nip",
+}
+
+var studentImportColumnAliases = map[string]string{
+	"nim": "student_id",
+}
+
+// StudentImportRow adalah satu baris file import mahasiswa. Row adalah nomor baris pada file
+// (baris header adalah 1). Kolom kosong berarti nilai lama dipertahankan.
+type StudentImportRow struct {
+	Row          int
+	StudentID    string
+	FullName     string
+	Email        string
+	Username     string
+	ProgramStudy string
+	AcademicYear string
+	AdvisorNIP   string
+}
+
+// StudentAccount adalah data user dan profil mahasiswa yang dibandingkan saat upsert.
+type StudentAccount struct {
+	ID           string
+	UserID       string
+	StudentID    string
+	Username     string
+	Email        string
+	FullName     string
+	ProgramStudy string
+	AcademicYear string
+	AdvisorID    string
+}
+
+type StudentImportRowResult struct {
+	Row             int      `json:"row"`
+	StudentID       string   `json:"student_id"`
+	Username        string   `json:"username,omitempty"`
+	FullName        string   `json:"full_name,omitempty"`
+	Status          string   `json:"status"`
+	InitialPassword string   `json:"initial_password,omitempty"`
+	Errors          []string `json:"errors,omitempty"`
+}
+
+type StudentImportReport struct {
+	DryRun    bool                     `json:"dry_run"`
+	Total     int                      `json:"total"`
+	Created   int                      `json:"created"`
+	Updated   int                      `json:"updated"`
+	Unchanged int                      `json:"unchanged"`
+	Failed    int                      `json:"failed"`
+	Rows      []StudentImportRowResult `json:"rows"`
+}
+
+type ImportStudentsResponse struct {
+	Status string              `json:"status"`
+	Data   StudentImportReport `json:"data"`
+}
+
+// CSVRecords mengubah laporan menjadi baris CSV (dengan header) untuk diunduh.
+func (r StudentImportReport) CSVRecords() [][]string {
+	records := [][]string{{"row", "student_id", "username", "full_name", "status", "initial_password", "errors"}}
+	for _, row := range r.Rows {
+		records = append(records, []string{
+			strconv.Itoa(row.Row), row.StudentID, row.Username, row.FullName, row.Status,
+			row.InitialPassword, strings.Join(row.Errors, "; "),
+		})
+	}
+	return records
+}
+
+// ParseStudentImportRecords memetakan baris CSV/XLSX ke StudentImportRow. Baris pertama adalah
+// header; kesalahan header dikembalikan sebagai error. Baris kosong dilewati.
+func ParseStudentImportRecords(records [][]string) ([]StudentImportRow, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("file kosong, baris header wajib ada")
+	}
+
+	known := make(map[string]bool, len(StudentImportColumns))
+	for _, column := range StudentImportColumns {
+		known[column] = true
+	}
+
+	columns := make([]string, len(records[0]))
+	seen := make(map[string]bool)
+	for i, header := range records[0] {
+		column := strings.ToLower(strings.TrimSpace(header))
+		if column == "" {
+			continue
+		}
+		if alias, ok := studentImportColumnAliases[column]; ok {
+			column = alias
+		}
+		if !known[column] {
+			return nil, fmt.Errorf("kolom %q tidak dikenali", header)
+		}
+		if seen[column] {
+			return nil, fmt.Errorf("kolom %q ditulis lebih dari sekali", header)
+		}
+		seen[column] = true
+		columns[i] = column
+	}
+	if !seen["student_id"] {
+		return nil, fmt.Errorf("kolom student_id (NIM) wajib ada")
+	}
+
+	rows := []StudentImportRow{}
+	for i, record := range records[1:] {
+		blank := true
+		row := StudentImportRow{Row: i + 2}
+		for j, value := range record {
+			value = strings.TrimSpace(value)
+			if value == "" || j >= len(columns) || columns[j] == "" {
+				continue
+			}
+			blank = false
+			switch columns[j] {
+			case "student_id":
+				row.StudentID = value
+			case "full_name":
+				row.FullName = value
+			case "email":
+				row.Email = strings.ToLower(value)
+			case "username":
+				row.Username = value
+			case "program_study":
+				row.ProgramStudy = value
+			case "academic_year":
+				row.AcademicYear = value
+			case "advisor_nip":
+				row.AdvisorNIP = value
+			}
+		}
+		if blank {
+			continue
+		}
+		if len(rows) == MaxStudentImportRows {
+			return nil, fmt.Errorf("jumlah baris melebihi batas %d", MaxStudentImportRows)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
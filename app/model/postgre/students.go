@@ -27,9 +27,18 @@ type UpdateStudentRequest struct {
 	AdvisorID    string `json:"advisor_id"`
 }
 
+type StudentFilter struct {
+	Search       string
+	AdvisorID    string
+	AcademicYear string
+	Limit        int
+	Offset       int
+}
+
 type GetAllStudentsResponse struct {
-	Status string    `json:"status"`
-	Data   []Student `json:"data"`
+	Status     string     `json:"status"`
+	Data       []Student  `json:"data"`
+	Pagination Pagination `json:"pagination"`
 }
 
 type GetStudentByIDResponse struct {
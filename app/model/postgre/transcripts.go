@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// Transcript adalah catatan transkrip prestasi yang diterbitkan untuk seorang mahasiswa.
+// VerificationCode adalah kode bertanda tangan dari ID transkrip yang dicetak pada dokumen.
+type Transcript struct {
+	ID                      string     `json:"id"`
+	StudentID               string     `json:"student_id"`
+	IssuedBy                *string    `json:"issued_by"`
+	AchievementReferenceIDs []string   `json:"achievement_reference_ids"`
+	TotalPoints             int        `json:"total_points"`
+	IssuedAt                time.Time  `json:"issued_at"`
+	RevokedAt               *time.Time `json:"revoked_at"`
+	RevokedBy               *string    `json:"revoked_by"`
+	RevocationReason        *string    `json:"revocation_reason"`
+	VerificationCode        string     `json:"verification_code,omitempty"`
+}
+
+type RevokeTranscriptRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+type GetTranscriptsResponse struct {
+	Status string       `json:"status"`
+	Data   []Transcript `json:"data"`
+}
+
+type RevokeTranscriptResponse struct {
+	Status string     `json:"status"`
+	Data   Transcript `json:"data"`
+}
+
+// TranscriptStudent adalah identitas mahasiswa yang dicetak pada kepala transkrip.
+type TranscriptStudent struct {
+	ID            string
+	StudentNumber string
+	FullName      string
+	ProgramStudy  string
+	AcademicYear  string
+	AdvisorName   string
+}
+
+// TranscriptEntry adalah prestasi terverifikasi milik mahasiswa, termasuk prestasi tim yang
+// diikutinya. ParticipantPoints berisi bagian poin mahasiswa pada prestasi tim.
+type TranscriptEntry struct {
+	ReferenceID        string
+	MongoAchievementID string
+	VerifiedAt         *time.Time
+	VerifierName       string
+	ParticipantRole    *string
+	ParticipantPoints  *int
+}
@@ -6,7 +6,7 @@ type User struct {
 	ID           string    `json:"id"`
 	Username     string    `json:"username"`
 	Email        string    `json:"email"`
-	PasswordHash string    `json:"password_hash"`
+	PasswordHash string    `json:"-"`
 	FullName     string    `json:"full_name"`
 	RoleID       string    `json:"role_id"`
 	IsActive     bool      `json:"is_active"`
@@ -30,9 +30,18 @@ type UpdateUserRequest struct {
 	IsActive *bool  `json:"is_active"`
 }
 
+type UserFilter struct {
+	Search   string
+	RoleID   string
+	IsActive *bool
+	Limit    int
+	Offset   int
+}
+
 type GetAllUsersResponse struct {
-	Status string `json:"status"`
-	Data   []User `json:"data"`
+	Status     string     `json:"status"`
+	Data       []User     `json:"data"`
+	Pagination Pagination `json:"pagination"`
 }
 
 type GetUserByIDResponse struct {
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+const (
+	VerificationTypeTranscript  = "transcript"
+	VerificationTypeAchievement = "achievement"
+)
+
+const (
+	VerificationStatusValid   = "valid"
+	VerificationStatusRevoked = "revoked"
+)
+
+// VerificationAchievement adalah ringkasan prestasi yang ditampilkan ke pemeriksa publik.
+type VerificationAchievement struct {
+	Title      string     `json:"title,omitempty"`
+	Status     string     `json:"status"`
+	VerifiedAt *time.Time `json:"verified_at,omitempty"`
+}
+
+// VerificationSummary adalah hasil pemeriksaan kode verifikasi. Dokumen yang dicabut hanya
+// mengembalikan tipe, status revoked, dan waktu pencabutan tanpa data mahasiswa.
+type VerificationSummary struct {
+	Type         string                    `json:"type"`
+	Status       string                    `json:"status"`
+	StudentName  string                    `json:"student_name,omitempty"`
+	IssuedAt     *time.Time                `json:"issued_at,omitempty"`
+	RevokedAt    *time.Time                `json:"revoked_at,omitempty"`
+	Achievements []VerificationAchievement `json:"achievements,omitempty"`
+}
+
+type VerifyCodeResponse struct {
+	Status string              `json:"status"`
+	Data   VerificationSummary `json:"data"`
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"regexp"
+	"strings"
 	"time"
 
 	model "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
@@ -9,6 +11,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func CreateAchievement(db *mongo.Database, achievement model.Achievement) (*model.Achievement, error) {
@@ -29,6 +32,24 @@ func CreateAchievement(db *mongo.Database, achievement model.Achievement) (*mode
 	return &achievement, nil
 }
 
+// CreateAchievements menyimpan banyak dokumen sekaligus. ID dokumen harus sudah diisi.
+func CreateAchievements(db *mongo.Database, achievements []model.Achievement) error {
+	if len(achievements) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	documents := make([]interface{}, len(achievements))
+	for i, achievement := range achievements {
+		documents[i] = achievement
+	}
+
+	_, err := db.Collection("achievements").InsertMany(ctx, documents)
+	return err
+}
+
 func GetAchievementByID(db *mongo.Database, id string) (*model.Achievement, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -88,6 +109,9 @@ func UpdateAchievement(db *mongo.Database, id string, req model.UpdateAchievemen
 	if req.Points != nil {
 		update["points"] = *req.Points
 	}
+	if req.PointRubricVersion != nil {
+		update["pointRubricVersion"] = *req.PointRubricVersion
+	}
 
 	_, err = collection.UpdateOne(
 		ctx,
@@ -114,7 +138,7 @@ func DeleteAchievement(db *mongo.Database, id string) error {
 	now := time.Now()
 	_, err = collection.UpdateOne(
 		ctx,
-		bson.M{"_id": objectID},
+		bson.M{"_id": objectID, "deletedAt": bson.M{"$exists": false}},
 		bson.M{"$set": bson.M{
 			"deletedAt": now,
 			"updatedAt": now,
@@ -123,6 +147,64 @@ func DeleteAchievement(db *mongo.Database, id string) error {
 	return err
 }
 
+// PurgeAchievement menghapus dokumen beserta revisinya secara permanen. Dipakai untuk
+// mengkompensasi dokumen yang tidak pernah mendapat reference di PostgreSQL; dokumen yang sudah
+// tidak ada dianggap berhasil.
+func PurgeAchievement(db *mongo.Database, id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	if _, err = db.Collection("achievement_revisions").DeleteMany(ctx, bson.M{"achievementId": objectID}); err != nil {
+		return err
+	}
+
+	_, err = db.Collection("achievements").DeleteOne(ctx, bson.M{"_id": objectID})
+	return err
+}
+
+func PurgeAchievements(db *mongo.Database, ids []primitive.ObjectID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	if _, err := db.Collection("achievement_revisions").DeleteMany(ctx, bson.M{"achievementId": bson.M{"$in": ids}}); err != nil {
+		return err
+	}
+
+	_, err := db.Collection("achievements").DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	return err
+}
+
+func UpdateAchievementPoints(db *mongo.Database, id string, points int, pointRubricVersion int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	collection := db.Collection("achievements")
+	_, err = collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objectID},
+		bson.M{"$set": bson.M{
+			"points":             points,
+			"pointRubricVersion": pointRubricVersion,
+			"updatedAt":          time.Now(),
+		}},
+	)
+	return err
+}
+
 func GetAchievementsByStudentID(db *mongo.Database, studentID string) ([]model.Achievement, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -180,3 +262,328 @@ func GetAchievementsByIDs(db *mongo.Database, ids []string) ([]model.Achievement
 	return achievements, nil
 }
 
+
+// GetAchievementSyncStates mengambil field yang dibutuhkan rekonsiliasi dari seluruh dokumen,
+// termasuk dokumen yang sudah di-soft delete.
+func GetAchievementSyncStates(db *mongo.Database) ([]model.Achievement, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	projection := bson.M{"_id": 1, "studentId": 1, "deletedAt": 1, "createdAt": 1}
+	cursor, err := db.Collection("achievements").Find(ctx, bson.M{}, options.Find().SetProjection(projection))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	achievements := []model.Achievement{}
+	if err = cursor.All(ctx, &achievements); err != nil {
+		return nil, err
+	}
+
+	return achievements, nil
+}
+
+func UpdateAchievementStudentID(db *mongo.Database, id string, studentID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("achievements").UpdateOne(
+		ctx,
+		bson.M{"_id": objectID},
+		bson.M{"$set": bson.M{
+			"studentId": studentID,
+			"updatedAt": time.Now(),
+		}},
+	)
+	return err
+}
+
+func GetAchievementsByFilter(db *mongo.Database, filter model.AchievementFilter) ([]model.Achievement, int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := buildAchievementFilterQuery(filter)
+	if query == nil {
+		return []model.Achievement{}, 0, nil
+	}
+
+	collection := db.Collection("achievements")
+	total, err := collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sortField := filter.SortField
+	if sortField == "" {
+		sortField = "createdAt"
+	}
+	sortOrder := filter.SortOrder
+	if sortOrder == 0 {
+		sortOrder = -1
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: sortField, Value: sortOrder}, {Key: "_id", Value: sortOrder}}).
+		SetSkip(int64(filter.Offset)).
+		SetLimit(int64(filter.Limit))
+
+	cursor, err := collection.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	achievements := []model.Achievement{}
+	if err = cursor.All(ctx, &achievements); err != nil {
+		return nil, 0, err
+	}
+
+	return achievements, int(total), nil
+}
+
+// ForEachAchievementByFilter memanggil fn untuk setiap achievement yang cocok dengan filter, dibaca
+// satu per satu dari cursor tanpa pagination sehingga hasil besar tidak dimuat sekaligus ke memori.
+// Iterasi berhenti pada error pertama dari fn.
+func ForEachAchievementByFilter(ctx context.Context, db *mongo.Database, filter model.AchievementFilter, fn func(model.Achievement) error) error {
+	query := buildAchievementFilterQuery(filter)
+	if query == nil {
+		return nil
+	}
+
+	sortField := filter.SortField
+	if sortField == "" {
+		sortField = "createdAt"
+	}
+	sortOrder := filter.SortOrder
+	if sortOrder == 0 {
+		sortOrder = -1
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: sortField, Value: sortOrder}, {Key: "_id", Value: sortOrder}}).
+		SetBatchSize(200)
+
+	cursor, err := db.Collection("achievements").Find(ctx, query, findOptions)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var achievement model.Achievement
+		if err := cursor.Decode(&achievement); err != nil {
+			return err
+		}
+		if err := fn(achievement); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+func SearchAchievements(db *mongo.Database, search string, filter model.AchievementFilter) ([]model.AchievementSearchResult, int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := buildAchievementFilterQuery(filter)
+	if query == nil {
+		return []model.AchievementSearchResult{}, 0, nil
+	}
+	query["$text"] = bson.M{"$search": search}
+
+	collection := db.Collection("achievements")
+	total, err := collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	score := bson.M{"$meta": "textScore"}
+	findOptions := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(filter.Offset)).
+		SetLimit(int64(filter.Limit))
+
+	cursor, err := collection.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []model.AchievementSearchResult{}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, 0, err
+	}
+
+	return results, int(total), nil
+}
+
+// buildAchievementFilterQuery menyusun query MongoDB dari filter prestasi. Nilai nil
+// dikembalikan jika filter tidak memuat ID valid sehingga hasilnya pasti kosong.
+func buildAchievementFilterQuery(filter model.AchievementFilter) bson.M {
+	var objectIDs []primitive.ObjectID
+	for _, id := range filter.IDs {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			continue
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+
+	if len(objectIDs) == 0 {
+		return nil
+	}
+
+	query := bson.M{
+		"_id":       bson.M{"$in": objectIDs},
+		"deletedAt": bson.M{"$exists": false},
+	}
+
+	if filter.AchievementType != "" {
+		query["achievementType"] = filter.AchievementType
+	}
+	if len(filter.Tags) > 0 {
+		query["tags"] = bson.M{"$in": filter.Tags}
+	}
+
+	points := bson.M{}
+	if filter.MinPoints != nil {
+		points["$gte"] = *filter.MinPoints
+	}
+	if filter.MaxPoints != nil {
+		points["$lte"] = *filter.MaxPoints
+	}
+	if len(points) > 0 {
+		query["points"] = points
+	}
+
+	createdAt := bson.M{}
+	if filter.DateFrom != nil {
+		createdAt["$gte"] = *filter.DateFrom
+	}
+	if filter.DateTo != nil {
+		createdAt["$lt"] = *filter.DateTo
+	}
+	if len(createdAt) > 0 {
+		query["createdAt"] = createdAt
+	}
+
+	return query
+}
+
+// FindDuplicateCandidates mencari prestasi aktif dengan tipe yang sama yang memiliki nomor
+// sertifikat, nama kompetisi, ISSN, atau checksum lampiran yang sama, ditambah hasil text search
+// judul. Penilaian kemiripan dilakukan oleh pemanggil.
+func FindDuplicateCandidates(db *mongo.Database, filter model.DuplicateCandidateFilter, limit int) ([]model.Achievement, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := db.Collection("achievements")
+	base := bson.M{
+		"_id":             bson.M{"$ne": filter.ExcludeID},
+		"achievementType": filter.AchievementType,
+		"deletedAt":       bson.M{"$exists": false},
+	}
+
+	exactMatch := func(value *string) interface{} {
+		return primitive.Regex{Pattern: "^" + regexp.QuoteMeta(strings.TrimSpace(*value)) + "$", Options: "i"}
+	}
+
+	conditions := bson.A{}
+	if filter.CertificationNumber != nil && strings.TrimSpace(*filter.CertificationNumber) != "" {
+		conditions = append(conditions, bson.M{"details.certificationNumber": exactMatch(filter.CertificationNumber)})
+	}
+	if filter.CompetitionName != nil && strings.TrimSpace(*filter.CompetitionName) != "" {
+		conditions = append(conditions, bson.M{"details.competitionName": exactMatch(filter.CompetitionName)})
+	}
+	if filter.ISSN != nil && strings.TrimSpace(*filter.ISSN) != "" {
+		conditions = append(conditions, bson.M{"details.issn": exactMatch(filter.ISSN)})
+	}
+	if len(filter.Checksums) > 0 {
+		conditions = append(conditions, bson.M{"attachments.checksum": bson.M{"$in": filter.Checksums}})
+	}
+
+	seen := make(map[primitive.ObjectID]bool)
+	candidates := []model.Achievement{}
+	collect := func(query bson.M, findOptions *options.FindOptions) error {
+		cursor, err := collection.Find(ctx, query, findOptions)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+
+		var achievements []model.Achievement
+		if err := cursor.All(ctx, &achievements); err != nil {
+			return err
+		}
+		for _, achievement := range achievements {
+			if !seen[achievement.ID] {
+				seen[achievement.ID] = true
+				candidates = append(candidates, achievement)
+			}
+		}
+		return nil
+	}
+
+	if len(conditions) > 0 {
+		query := bson.M{"$or": conditions}
+		for key, value := range base {
+			query[key] = value
+		}
+		if err := collect(query, options.Find().SetLimit(int64(limit))); err != nil {
+			return nil, err
+		}
+	}
+
+	if strings.TrimSpace(filter.Title) != "" {
+		query := bson.M{"$text": bson.M{"$search": filter.Title}}
+		for key, value := range base {
+			query[key] = value
+		}
+		score := bson.M{"$meta": "textScore"}
+		findOptions := options.Find().
+			SetProjection(bson.M{"score": score}).
+			SetSort(bson.D{{Key: "score", Value: score}}).
+			SetLimit(int64(limit))
+		if err := collect(query, findOptions); err != nil {
+			return nil, err
+		}
+	}
+
+	return candidates, nil
+}
+
+// GetAchievementsForDuplicateScan mengambil field yang dibutuhkan laporan duplikat dari seluruh
+// prestasi aktif.
+func GetAchievementsForDuplicateScan(db *mongo.Database) ([]model.Achievement, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	projection := bson.M{
+		"_id": 1, "studentId": 1, "achievementType": 1, "title": 1, "createdAt": 1,
+		"details.certificationNumber": 1, "details.competitionName": 1, "details.eventDate": 1,
+		"details.issn": 1, "details.publicationTitle": 1, "attachments.checksum": 1,
+	}
+	cursor, err := db.Collection("achievements").Find(ctx,
+		bson.M{"deletedAt": bson.M{"$exists": false}},
+		options.Find().SetProjection(projection),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	achievements := []model.Achievement{}
+	if err = cursor.All(ctx, &achievements); err != nil {
+		return nil, err
+	}
+
+	return achievements, nil
+}
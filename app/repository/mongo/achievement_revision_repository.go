@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	model "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxRevisionNumberAttempts membatasi percobaan ulang saat dua penulisan bersamaan mendapat
+// nomor revisi yang sama (ditolak oleh unique index achievementId + revision).
+const maxRevisionNumberAttempts = 3
+
+// CreateAchievementRevision menyimpan revisi baru dengan nomor revisi berikutnya untuk prestasi
+// yang sama. Field Revision dan CreatedAt diisi oleh fungsi ini.
+func CreateAchievementRevision(db *mongo.Database, revision model.AchievementRevision) (*model.AchievementRevision, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := db.Collection("achievement_revisions")
+	revision.ID = primitive.NewObjectID()
+	revision.CreatedAt = time.Now()
+
+	var err error
+	for attempt := 0; attempt < maxRevisionNumberAttempts; attempt++ {
+		var latest model.AchievementRevision
+		err = collection.FindOne(ctx,
+			bson.M{"achievementId": revision.AchievementID},
+			options.FindOne().SetSort(bson.M{"revision": -1}).SetProjection(bson.M{"revision": 1}),
+		).Decode(&latest)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return nil, err
+		}
+		revision.Revision = latest.Revision + 1
+
+		_, err = collection.InsertOne(ctx, revision)
+		if err == nil {
+			return &revision, nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return nil, err
+		}
+	}
+
+	return nil, err
+}
+
+func GetAchievementRevisions(db *mongo.Database, achievementID string) ([]model.AchievementRevision, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(achievementID)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := db.Collection("achievement_revisions").Find(ctx,
+		bson.M{"achievementId": objectID},
+		options.Find().SetSort(bson.M{"revision": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	revisions := []model.AchievementRevision{}
+	if err = cursor.All(ctx, &revisions); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// GetAchievementRevision mengambil satu revisi. Mengembalikan mongo.ErrNoDocuments jika revisi
+// tidak ada.
+func GetAchievementRevision(db *mongo.Database, achievementID string, revisionNumber int) (*model.AchievementRevision, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(achievementID)
+	if err != nil {
+		return nil, err
+	}
+
+	var revision model.AchievementRevision
+	err = db.Collection("achievement_revisions").FindOne(ctx, bson.M{
+		"achievementId": objectID,
+		"revision":      revisionNumber,
+	}).Decode(&revision)
+	if err != nil {
+		return nil, err
+	}
+
+	return &revision, nil
+}
+
+// GetLatestAchievementRevision mengambil revisi terakhir. Mengembalikan mongo.ErrNoDocuments jika
+// prestasi belum memiliki revisi.
+func GetLatestAchievementRevision(db *mongo.Database, achievementID string) (*model.AchievementRevision, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(achievementID)
+	if err != nil {
+		return nil, err
+	}
+
+	var revision model.AchievementRevision
+	err = db.Collection("achievement_revisions").FindOne(ctx,
+		bson.M{"achievementId": objectID},
+		options.FindOne().SetSort(bson.M{"revision": -1}),
+	).Decode(&revision)
+	if err != nil {
+		return nil, err
+	}
+
+	return &revision, nil
+}
+
+// DeleteAchievementRevision menghapus revisi yang isinya tidak pernah tersimpan ke dokumen
+// prestasi, yaitu saat update dokumen gagal setelah revisi dicatat.
+func DeleteAchievementRevision(db *mongo.Database, id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := db.Collection("achievement_revisions").DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"database/sql"
+	model "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+)
+
+const achievementCommentColumns = `
+	c.id, c.achievement_reference_id, c.author_id, u.full_name, r.name,
+	c.body, c.attachment_name, c.attachment_url, c.created_at, c.updated_at
+`
+
+const achievementCommentJoins = `
+	FROM achievement_comments c
+	LEFT JOIN users u ON c.author_id = u.id
+	LEFT JOIN roles r ON u.role_id = r.id
+`
+
+func scanAchievementComment(row rowScanner) (*model.AchievementComment, error) {
+	var comment model.AchievementComment
+	err := row.Scan(
+		&comment.ID, &comment.AchievementReferenceID, &comment.AuthorID, &comment.AuthorName, &comment.AuthorRole,
+		&comment.Body, &comment.AttachmentName, &comment.AttachmentURL, &comment.CreatedAt, &comment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// GetAchievementComments mengambil komentar yang belum dihapus, diurutkan dari yang terlama.
+func GetAchievementComments(db *sql.DB, referenceID string) ([]model.AchievementComment, error) {
+	query := `SELECT` + achievementCommentColumns + achievementCommentJoins + `
+		WHERE c.achievement_reference_id = $1 AND c.deleted_at IS NULL
+		ORDER BY c.created_at ASC, c.id ASC
+	`
+
+	rows, err := db.Query(query, referenceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []model.AchievementComment{}
+	for rows.Next() {
+		comment, err := scanAchievementComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, *comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// GetAchievementCommentByID mengambil komentar yang belum dihapus pada reference tertentu.
+// Mengembalikan sql.ErrNoRows jika komentar tidak ada.
+func GetAchievementCommentByID(db *sql.DB, referenceID string, commentID string) (*model.AchievementComment, error) {
+	query := `SELECT` + achievementCommentColumns + achievementCommentJoins + `
+		WHERE c.id = $1 AND c.achievement_reference_id = $2 AND c.deleted_at IS NULL
+	`
+	return scanAchievementComment(db.QueryRow(query, commentID, referenceID))
+}
+
+func CreateAchievementComment(db *sql.DB, referenceID string, authorID string, req model.CreateAchievementCommentRequest) (*model.AchievementComment, error) {
+	var id string
+	err := db.QueryRow(`
+		INSERT INTO achievement_comments (achievement_reference_id, author_id, body, attachment_name, attachment_url)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, referenceID, authorID, req.Body, req.AttachmentName, req.AttachmentURL).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetAchievementCommentByID(db, referenceID, id)
+}
+
+// UpdateAchievementComment mengubah isi komentar. Mengembalikan sql.ErrNoRows jika komentar
+// tidak ada atau sudah dihapus.
+func UpdateAchievementComment(db *sql.DB, referenceID string, commentID string, req model.CreateAchievementCommentRequest) (*model.AchievementComment, error) {
+	result, err := db.Exec(`
+		UPDATE achievement_comments
+		SET body = $1, attachment_name = $2, attachment_url = $3
+		WHERE id = $4 AND achievement_reference_id = $5 AND deleted_at IS NULL
+	`, req.Body, req.AttachmentName, req.AttachmentURL, commentID, referenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return GetAchievementCommentByID(db, referenceID, commentID)
+}
+
+// DeleteAchievementComment menandai komentar sebagai dihapus. Mengembalikan sql.ErrNoRows jika
+// komentar tidak ada atau sudah dihapus.
+func DeleteAchievementComment(db *sql.DB, referenceID string, commentID string) error {
+	result, err := db.Exec(`
+		UPDATE achievement_comments
+		SET deleted_at = NOW()
+		WHERE id = $1 AND achievement_reference_id = $2 AND deleted_at IS NULL
+	`, commentID, referenceID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
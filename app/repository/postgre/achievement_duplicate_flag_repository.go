@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"database/sql"
+	model "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+
+	"github.com/lib/pq"
+)
+
+const achievementDuplicateFlagColumns = `
+	id, achievement_reference_id, matched_mongo_achievement_id, score, reasons,
+	dismissed_by, dismissed_at, created_at, updated_at
+`
+
+func scanAchievementDuplicateFlag(row rowScanner) (*model.AchievementDuplicateFlag, error) {
+	var flag model.AchievementDuplicateFlag
+	err := row.Scan(
+		&flag.ID, &flag.AchievementReferenceID, &flag.MatchedMongoAchievementID, &flag.Score,
+		pq.Array(&flag.Reasons), &flag.DismissedBy, &flag.DismissedAt, &flag.CreatedAt, &flag.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &flag, nil
+}
+
+// UpsertAchievementDuplicateFlag mencatat atau memperbarui tanda duplikat. Tanda yang sudah
+// ditutup dosen wali tetap tertutup meskipun pemeriksaan berikutnya menemukan pasangan yang sama.
+func UpsertAchievementDuplicateFlag(db *sql.DB, referenceID string, matchedMongoID string, score float64, reasons []string) (*model.AchievementDuplicateFlag, error) {
+	query := `
+		INSERT INTO achievement_duplicate_flags (achievement_reference_id, matched_mongo_achievement_id, score, reasons)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (achievement_reference_id, matched_mongo_achievement_id)
+		DO UPDATE SET score = EXCLUDED.score, reasons = EXCLUDED.reasons
+		RETURNING ` + achievementDuplicateFlagColumns
+	return scanAchievementDuplicateFlag(db.QueryRow(query, referenceID, matchedMongoID, score, pq.Array(reasons)))
+}
+
+func GetAchievementDuplicateFlags(db *sql.DB, referenceID string) ([]model.AchievementDuplicateFlag, error) {
+	query := `SELECT ` + achievementDuplicateFlagColumns + `
+		FROM achievement_duplicate_flags
+		WHERE achievement_reference_id = $1
+		ORDER BY dismissed_at IS NOT NULL, score DESC, created_at ASC
+	`
+
+	rows, err := db.Query(query, referenceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	flags := []model.AchievementDuplicateFlag{}
+	for rows.Next() {
+		flag, err := scanAchievementDuplicateFlag(rows)
+		if err != nil {
+			return nil, err
+		}
+		flags = append(flags, *flag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return flags, nil
+}
+
+// DismissAchievementDuplicateFlag menutup tanda duplikat yang masih terbuka. Mengembalikan
+// sql.ErrNoRows jika tanda tidak ada atau sudah ditutup.
+func DismissAchievementDuplicateFlag(db *sql.DB, referenceID string, flagID string, dismissedBy string) error {
+	result, err := db.Exec(`
+		UPDATE achievement_duplicate_flags
+		SET dismissed_by = $1, dismissed_at = NOW()
+		WHERE id = $2 AND achievement_reference_id = $3 AND dismissed_at IS NULL
+	`, dismissedBy, flagID, referenceID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	model "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	"time"
+)
+
+// ErrAchievementOutboxNotPending dikembalikan jika entri outbox sudah diselesaikan atau
+// dikompensasi oleh proses lain sebelum operasi saat ini selesai.
+var ErrAchievementOutboxNotPending = errors.New("achievement outbox entry is no longer pending")
+
+const achievementOutboxColumns = `id, operation, mongo_achievement_id, achievement_reference_id, created_by, idempotency_key,
+	status, attempts, last_error, next_attempt_at, created_at, updated_at`
+
+func scanAchievementOutbox(row rowScanner) (*model.AchievementOutbox, error) {
+	var entry model.AchievementOutbox
+	err := row.Scan(
+		&entry.ID, &entry.Operation, &entry.MongoAchievementID, &entry.AchievementReferenceID,
+		&entry.CreatedBy, &entry.IdempotencyKey, &entry.Status, &entry.Attempts, &entry.LastError,
+		&entry.NextAttemptAt, &entry.CreatedAt, &entry.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// CreateAchievementOutbox mencatat niat operasi sebelum MongoDB disentuh. Worker baru akan
+// memproses entri ini setelah delay berlalu, sehingga request yang sedang berjalan sempat
+// menyelesaikannya sendiri.
+func CreateAchievementOutbox(db *sql.DB, operation string, mongoID string, createdBy string, idempotencyKey *string, delay time.Duration) (*model.AchievementOutbox, error) {
+	query := `
+		INSERT INTO achievement_outbox (operation, mongo_achievement_id, created_by, idempotency_key, next_attempt_at)
+		VALUES ($1, $2, $3, $4, NOW() + ($5 * INTERVAL '1 second'))
+		RETURNING ` + achievementOutboxColumns
+
+	return scanAchievementOutbox(db.QueryRow(query, operation, mongoID, createdBy, idempotencyKey, int(delay.Seconds())))
+}
+
+func GetAchievementOutboxByIdempotencyKey(db *sql.DB, createdBy string, idempotencyKey string) (*model.AchievementOutbox, error) {
+	query := `SELECT ` + achievementOutboxColumns + ` FROM achievement_outbox WHERE created_by = $1 AND idempotency_key = $2`
+	return scanAchievementOutbox(db.QueryRow(query, createdBy, idempotencyKey))
+}
+
+func DeleteAchievementOutbox(db *sql.DB, id string) error {
+	_, err := db.Exec(`DELETE FROM achievement_outbox WHERE id = $1`, id)
+	return err
+}
+
+// GetPendingAchievementOutboxMongoIDs mengembalikan ID dokumen MongoDB yang masih memiliki
+// operasi outbox pending.
+func GetPendingAchievementOutboxMongoIDs(db *sql.DB) (map[string]bool, error) {
+	rows, err := db.Query(`SELECT mongo_achievement_id FROM achievement_outbox WHERE status = 'pending'`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids[id] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// MarkAchievementOutboxStatus menutup entri yang masih pending. Entri yang sudah ditutup
+// proses lain menghasilkan ErrAchievementOutboxNotPending.
+func MarkAchievementOutboxStatus(db *sql.DB, id string, status string) error {
+	query := `UPDATE achievement_outbox SET status = $1 WHERE id = $2 AND status = 'pending'`
+	result, err := db.Exec(query, status, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrAchievementOutboxNotPending
+	}
+
+	return nil
+}
+
+// CompleteAchievementCreation membuat reference untuk dokumen MongoDB yang sudah tersimpan dan
+// menutup entri outbox-nya dalam satu transaksi. Jika worker sudah mengkompensasi entri tersebut,
+// reference tidak dibuat dan ErrAchievementOutboxNotPending dikembalikan. Reference yang langsung
+// dibuat dengan status verified (import) dicatat sebagai diverifikasi oleh createdBy.
+func CompleteAchievementCreation(db *sql.DB, outboxID string, req model.CreateAchievementReferenceRequest, createdBy string) (*model.AchievementReference, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRow(`SELECT status FROM achievement_outbox WHERE id = $1 FOR UPDATE`, outboxID).Scan(&status)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != model.AchievementOutboxStatusPending {
+		return nil, ErrAchievementOutboxNotPending
+	}
+
+	ref, err := insertAchievementReference(tx, req, createdBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if ref.Status == model.AchievementStatusVerified {
+		err := tx.QueryRow(`
+			UPDATE achievement_references
+			SET submitted_at = NOW(), verified_at = NOW(), verified_by = $1
+			WHERE id = $2
+			RETURNING submitted_at, verified_at, verified_by
+		`, createdBy, ref.ID).Scan(&ref.SubmittedAt, &ref.VerifiedAt, &ref.VerifiedBy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	query := `
+		UPDATE achievement_outbox
+		SET status = 'completed', achievement_reference_id = $1
+		WHERE id = $2
+	`
+	if _, err := tx.Exec(query, ref.ID, outboxID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return ref, nil
+}
+
+// DeleteAchievementReferenceWithOutbox menandai reference sebagai deleted dan mencatat entri
+// outbox untuk soft delete dokumen MongoDB dalam satu transaksi. sql.ErrNoRows dikembalikan
+// jika status reference sudah berubah sehingga tidak dapat dihapus lagi.
+func DeleteAchievementReferenceWithOutbox(db *sql.DB, ref model.AchievementReference, changedBy string, delay time.Duration) (string, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	fromStatus, err := lockAchievementReferenceStatus(tx, ref.ID)
+	if err != nil {
+		return "", err
+	}
+
+	if !model.CanTransitionAchievementStatus(fromStatus, model.AchievementStatusDeleted) {
+		return "", sql.ErrNoRows
+	}
+
+	query := `UPDATE achievement_references SET status = 'deleted', updated_at = NOW() WHERE id = $1`
+	if _, err := tx.Exec(query, ref.ID); err != nil {
+		return "", err
+	}
+
+	if err := insertAchievementStatusHistory(tx, ref.ID, &fromStatus, model.AchievementStatusDeleted, changedBy, nil); err != nil {
+		return "", err
+	}
+
+	outboxID, err := insertAchievementOutbox(tx, model.AchievementOutboxOperationDelete, ref.MongoAchievementID, ref.ID, changedBy, delay)
+	if err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	return outboxID, nil
+}
+
+// insertAchievementOutbox mencatat entri outbox untuk reference yang sedang diubah di dalam tx,
+// sehingga entri hanya ada jika perubahan PostgreSQL ikut di-commit.
+func insertAchievementOutbox(tx *sql.Tx, operation string, mongoID string, referenceID string, createdBy string, delay time.Duration) (string, error) {
+	query := `
+		INSERT INTO achievement_outbox (operation, mongo_achievement_id, achievement_reference_id, created_by, next_attempt_at)
+		VALUES ($1, $2, $3, $4, NOW() + ($5 * INTERVAL '1 second'))
+		RETURNING id
+	`
+	var outboxID string
+	err := tx.QueryRow(query, operation, mongoID, referenceID, createdBy, int(delay.Seconds())).Scan(&outboxID)
+	return outboxID, err
+}
+
+// ProcessNextAchievementOutbox mengunci satu entri pending yang sudah jatuh tempo dan menyerahkannya
+// ke apply beserta informasi apakah reference untuk dokumen tersebut sudah ada. Status hasil apply
+// disimpan; jika apply gagal, percobaan dijadwalkan ulang dengan backoff sampai maxAttempts tercapai.
+// Nilai false berarti tidak ada entri yang perlu diproses.
+func ProcessNextAchievementOutbox(db *sql.DB, maxAttempts int, apply func(entry model.AchievementOutbox, referenceExists bool) (string, error)) (bool, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT ` + achievementOutboxColumns + `
+		FROM achievement_outbox
+		WHERE status = 'pending' AND next_attempt_at <= NOW()
+		ORDER BY next_attempt_at
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+	entry, err := scanAchievementOutbox(tx.QueryRow(query))
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var referenceExists bool
+	query = `SELECT EXISTS(SELECT 1 FROM achievement_references WHERE mongo_achievement_id = $1)`
+	if err := tx.QueryRow(query, entry.MongoAchievementID).Scan(&referenceExists); err != nil {
+		return false, err
+	}
+
+	status, applyErr := apply(*entry, referenceExists)
+	if applyErr == nil {
+		if _, err := tx.Exec(`UPDATE achievement_outbox SET status = $1, last_error = NULL WHERE id = $2`, status, entry.ID); err != nil {
+			return false, err
+		}
+		return true, tx.Commit()
+	}
+
+	attempts := entry.Attempts + 1
+	status = model.AchievementOutboxStatusPending
+	if attempts >= maxAttempts {
+		status = model.AchievementOutboxStatusFailed
+	}
+
+	backoff := time.Duration(1<<uint(attempts)) * 30 * time.Second
+	if backoff > time.Hour {
+		backoff = time.Hour
+	}
+
+	query = `
+		UPDATE achievement_outbox
+		SET status = $1, attempts = $2, last_error = $3, next_attempt_at = NOW() + ($4 * INTERVAL '1 second')
+		WHERE id = $5
+	`
+	if _, err := tx.Exec(query, status, attempts, applyErr.Error(), int(backoff.Seconds()), entry.ID); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"database/sql"
+	model "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+
+	"github.com/lib/pq"
+)
+
+// GetAchievementParticipants mengambil peserta prestasi tim, leader lebih dulu. Prestasi individu
+// tidak memiliki peserta.
+func GetAchievementParticipants(db *sql.DB, referenceID string) ([]model.AchievementParticipant, error) {
+	query := `
+		SELECT ap.id, ap.achievement_reference_id, ap.student_id, s.student_id, u.full_name,
+		       ap.role, ap.points, ap.created_at
+		FROM achievement_participants ap
+		INNER JOIN students s ON ap.student_id = s.id
+		INNER JOIN users u ON s.user_id = u.id
+		WHERE ap.achievement_reference_id = $1
+		ORDER BY ap.role = 'leader' DESC, u.full_name ASC
+	`
+
+	rows, err := db.Query(query, referenceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	participants := []model.AchievementParticipant{}
+	for rows.Next() {
+		var participant model.AchievementParticipant
+		err := rows.Scan(
+			&participant.ID, &participant.AchievementReferenceID, &participant.StudentID, &participant.StudentNumber,
+			&participant.FullName, &participant.Role, &participant.Points, &participant.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		participants = append(participants, participant)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return participants, nil
+}
+
+// IsAchievementParticipantInScope menandakan salah satu peserta prestasi adalah studentID atau,
+// jika advisorID diisi, mahasiswa bimbingan advisorID.
+func IsAchievementParticipantInScope(db *sql.DB, referenceID string, studentID string, advisorID string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM achievement_participants ap
+			INNER JOIN students s ON ap.student_id = s.id
+			WHERE ap.achievement_reference_id = $1
+			  AND (($2 <> '' AND ap.student_id::text = $2) OR ($3 <> '' AND s.advisor_id::text = $3))
+		)
+	`
+	var exists bool
+	err := db.QueryRow(query, referenceID, studentID, advisorID).Scan(&exists)
+	return exists, err
+}
+
+// CountExistingStudents menghitung berapa ID pada studentIDs yang ada di tabel students.
+func CountExistingStudents(db *sql.DB, studentIDs []string) (int, error) {
+	query := `SELECT COUNT(*) FROM students WHERE id::text = ANY($1)`
+	var count int
+	err := db.QueryRow(query, pq.Array(studentIDs)).Scan(&count)
+	return count, err
+}
+
+// SetAchievementParticipants mengganti seluruh peserta prestasi selama statusnya masih dapat
+// diedit. Seperti update konten, prestasi rejected kembali menjadi draft. Mengembalikan
+// sql.ErrNoRows jika status sudah berubah sehingga prestasi tidak dapat diedit.
+func SetAchievementParticipants(db *sql.DB, referenceID string, participants []model.AchievementParticipantInput, changedBy string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	fromStatus, err := lockAchievementReferenceStatus(tx, referenceID)
+	if err != nil {
+		return err
+	}
+	if !model.IsAchievementEditable(fromStatus) {
+		return sql.ErrNoRows
+	}
+
+	if _, err := tx.Exec(`DELETE FROM achievement_participants WHERE achievement_reference_id = $1`, referenceID); err != nil {
+		return err
+	}
+
+	for _, participant := range participants {
+		_, err := tx.Exec(`
+			INSERT INTO achievement_participants (achievement_reference_id, student_id, role)
+			VALUES ($1, $2, $3)
+		`, referenceID, participant.StudentID, participant.Role)
+		if err != nil {
+			return err
+		}
+	}
+
+	if fromStatus != model.AchievementStatusDraft {
+		if _, err := tx.Exec(`UPDATE achievement_references SET status = 'draft', updated_at = NOW() WHERE id = $1`, referenceID); err != nil {
+			return err
+		}
+		if err := insertAchievementStatusHistory(tx, referenceID, &fromStatus, model.AchievementStatusDraft, changedBy, nil); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func setAchievementParticipantPoints(tx *sql.Tx, referenceID string, participantPoints map[string]int) error {
+	for studentID, points := range participantPoints {
+		_, err := tx.Exec(`
+			UPDATE achievement_participants SET points = $1
+			WHERE achievement_reference_id = $2 AND student_id = $3
+		`, points, referenceID, studentID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
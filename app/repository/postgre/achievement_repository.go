@@ -2,11 +2,34 @@ package repository
 
 import (
 	"database/sql"
+	"fmt"
 	model "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	"strings"
 	"time"
+
+	"github.com/lib/pq"
 )
 
-func CreateAchievementReference(db *sql.DB, req model.CreateAchievementReferenceRequest) (*model.AchievementReference, error) {
+func CreateAchievementReference(db *sql.DB, req model.CreateAchievementReferenceRequest, createdBy string) (*model.AchievementReference, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ref, err := insertAchievementReference(tx, req, createdBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return ref, nil
+}
+
+func insertAchievementReference(tx *sql.Tx, req model.CreateAchievementReferenceRequest, createdBy string) (*model.AchievementReference, error) {
 	query := `
 		INSERT INTO achievement_references (student_id, mongo_achievement_id, status, created_at, updated_at)
 		VALUES ($1, $2, $3, NOW(), NOW())
@@ -15,7 +38,7 @@ func CreateAchievementReference(db *sql.DB, req model.CreateAchievementReference
 	`
 
 	ref := new(model.AchievementReference)
-	err := db.QueryRow(query, req.StudentID, req.MongoAchievementID, req.Status).Scan(
+	err := tx.QueryRow(query, req.StudentID, req.MongoAchievementID, req.Status).Scan(
 		&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status,
 		&ref.SubmittedAt, &ref.VerifiedAt, &ref.VerifiedBy, &ref.RejectionNote,
 		&ref.CreatedAt, &ref.UpdatedAt,
@@ -25,6 +48,10 @@ func CreateAchievementReference(db *sql.DB, req model.CreateAchievementReference
 		return nil, err
 	}
 
+	if err := insertAchievementStatusHistory(tx, ref.ID, nil, ref.Status, createdBy, nil); err != nil {
+		return nil, err
+	}
+
 	return ref, nil
 }
 
@@ -72,29 +99,229 @@ func GetAchievementReferenceByID(db *sql.DB, id string) (*model.AchievementRefer
 	return ref, nil
 }
 
-func UpdateAchievementReferenceStatus(db *sql.DB, id string, status string, submittedAt *time.Time) error {
-	var query string
-	var err error
+// GetAchievementReferencesByIDs mengambil reference berdasarkan ID, termasuk yang berstatus deleted.
+func GetAchievementReferencesByIDs(db *sql.DB, ids []string) ([]model.AchievementReference, error) {
+	if len(ids) == 0 {
+		return []model.AchievementReference{}, nil
+	}
+
+	query := `
+		SELECT id, student_id, mongo_achievement_id, status, submitted_at,
+		       verified_at, verified_by, rejection_note, created_at, updated_at
+		FROM achievement_references
+		WHERE id = ANY($1)
+	`
+
+	rows, err := db.Query(query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	references := []model.AchievementReference{}
+	for rows.Next() {
+		var ref model.AchievementReference
+		err := rows.Scan(
+			&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status,
+			&ref.SubmittedAt, &ref.VerifiedAt, &ref.VerifiedBy, &ref.RejectionNote,
+			&ref.CreatedAt, &ref.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		references = append(references, ref)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return references, nil
+}
+
+// UpdateAchievementReferenceStatus memindahkan status reference dan mencatat riwayatnya. Aturan
+// perpindahan diperiksa ulang setelah baris dikunci; sql.ErrNoRows dikembalikan jika status sudah
+// berubah sehingga perpindahan tidak lagi diizinkan.
+func UpdateAchievementReferenceStatus(db *sql.DB, id string, status string, submittedAt *time.Time, changedBy string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	fromStatus, err := lockAchievementReferenceStatus(tx, id)
+	if err != nil {
+		return err
+	}
+
+	if !model.CanTransitionAchievementStatus(fromStatus, status) {
+		return sql.ErrNoRows
+	}
 
 	if submittedAt != nil {
-		query = `
+		query := `
 			UPDATE achievement_references
 			SET status = $1, submitted_at = $2, updated_at = NOW()
 			WHERE id = $3
 		`
-		_, err = db.Exec(query, status, submittedAt, id)
+		_, err = tx.Exec(query, status, submittedAt, id)
 	} else {
-		query = `
+		query := `
 			UPDATE achievement_references
 			SET status = $1, updated_at = NOW()
 			WHERE id = $2
 		`
-		_, err = db.Exec(query, status, id)
+		_, err = tx.Exec(query, status, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := insertAchievementStatusHistory(tx, id, &fromStatus, status, changedBy, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// VerifyAchievementReference memverifikasi prestasi yang berstatus submitted dan mencatat entri
+// outbox untuk pembaruan poin di MongoDB dalam satu transaksi. participantPoints berisi bagian poin
+// setiap peserta prestasi tim (key: students.id) dan nil untuk prestasi individu. ID entri outbox
+// dikembalikan agar pemanggil dapat menutupnya setelah poin tersimpan.
+func VerifyAchievementReference(db *sql.DB, ref model.AchievementReference, verifiedBy string, participantPoints map[string]int, outboxDelay time.Duration) (string, error) {
+	query := `
+		UPDATE achievement_references
+		SET status = 'verified', verified_at = NOW(), verified_by = $1, rejection_note = NULL, updated_at = NOW()
+		WHERE id = $2
+	`
+
+	tx, err := db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	fromStatus, err := lockAchievementReferenceStatus(tx, ref.ID)
+	if err != nil {
+		return "", err
+	}
+
+	if fromStatus != model.AchievementStatusSubmitted {
+		return "", sql.ErrNoRows
+	}
+
+	if _, err := tx.Exec(query, verifiedBy, ref.ID); err != nil {
+		return "", err
+	}
+
+	if err := insertAchievementStatusHistory(tx, ref.ID, &fromStatus, model.AchievementStatusVerified, verifiedBy, nil); err != nil {
+		return "", err
+	}
+
+	if err := setAchievementParticipantPoints(tx, ref.ID, participantPoints); err != nil {
+		return "", err
+	}
+
+	outboxID, err := insertAchievementOutbox(tx, model.AchievementOutboxOperationPoints, ref.MongoAchievementID, ref.ID, verifiedBy, outboxDelay)
+	if err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	return outboxID, nil
+}
+
+func RejectAchievementReference(db *sql.DB, id string, verifiedBy string, rejectionNote string) error {
+	query := `
+		UPDATE achievement_references
+		SET status = 'rejected', verified_at = NOW(), verified_by = $1, rejection_note = $2, updated_at = NOW()
+		WHERE id = $3
+	`
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	fromStatus, err := lockAchievementReferenceStatus(tx, id)
+	if err != nil {
+		return err
+	}
+
+	if fromStatus != model.AchievementStatusSubmitted {
+		return sql.ErrNoRows
+	}
+
+	if _, err := tx.Exec(query, verifiedBy, rejectionNote, id); err != nil {
+		return err
 	}
 
+	if err := insertAchievementStatusHistory(tx, id, &fromStatus, model.AchievementStatusRejected, verifiedBy, &rejectionNote); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func lockAchievementReferenceStatus(tx *sql.Tx, id string) (string, error) {
+	query := `SELECT status FROM achievement_references WHERE id = $1 FOR UPDATE`
+	var status string
+	err := tx.QueryRow(query, id).Scan(&status)
+	if err != nil {
+		return "", err
+	}
+	return status, nil
+}
+
+func insertAchievementStatusHistory(tx *sql.Tx, referenceID string, fromStatus *string, toStatus string, changedBy string, note *string) error {
+	query := `
+		INSERT INTO achievement_status_history (achievement_reference_id, from_status, to_status, changed_by, note)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := tx.Exec(query, referenceID, fromStatus, toStatus, changedBy, note)
 	return err
 }
 
+func GetAchievementStatusHistory(db *sql.DB, referenceID string) ([]model.AchievementStatusHistory, error) {
+	query := `
+		SELECT h.id, h.achievement_reference_id, h.from_status, h.to_status,
+		       h.changed_by, u.full_name, h.note, h.created_at
+		FROM achievement_status_history h
+		LEFT JOIN users u ON h.changed_by = u.id
+		WHERE h.achievement_reference_id = $1
+		ORDER BY h.created_at ASC
+	`
+
+	rows, err := db.Query(query, referenceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []model.AchievementStatusHistory{}
+	for rows.Next() {
+		var entry model.AchievementStatusHistory
+		err := rows.Scan(
+			&entry.ID, &entry.AchievementReferenceID, &entry.FromStatus, &entry.ToStatus,
+			&entry.ChangedBy, &entry.ChangedByName, &entry.Note, &entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
 func DeleteAchievementReference(db *sql.DB, id string) error {
 	query := `DELETE FROM achievement_references WHERE id = $1`
 	_, err := db.Exec(query, id)
@@ -210,6 +437,77 @@ func GetAllAchievementReferences(db *sql.DB) ([]model.AchievementReference, erro
 	return references, nil
 }
 
+// GetAllAchievementReferencesWithDeleted mengambil seluruh reference termasuk yang berstatus deleted.
+func GetAllAchievementReferencesWithDeleted(db *sql.DB) ([]model.AchievementReference, error) {
+	query := `
+		SELECT id, student_id, mongo_achievement_id, status, submitted_at,
+		       verified_at, verified_by, rejection_note, created_at, updated_at
+		FROM achievement_references
+		ORDER BY created_at
+	`
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	references := []model.AchievementReference{}
+	for rows.Next() {
+		var ref model.AchievementReference
+		err := rows.Scan(
+			&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status,
+			&ref.SubmittedAt, &ref.VerifiedAt, &ref.VerifiedBy, &ref.RejectionNote,
+			&ref.CreatedAt, &ref.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		references = append(references, ref)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return references, nil
+}
+
+// MarkAchievementReferenceDeleted menandai reference sebagai deleted tanpa memeriksa aturan
+// perpindahan status, untuk memperbaiki reference yang dokumen MongoDB-nya sudah tidak aktif.
+// Riwayat status dicatat tanpa changed_by. sql.ErrNoRows dikembalikan jika reference sudah deleted.
+func MarkAchievementReferenceDeleted(db *sql.DB, id string, note string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	fromStatus, err := lockAchievementReferenceStatus(tx, id)
+	if err != nil {
+		return err
+	}
+
+	if fromStatus == model.AchievementStatusDeleted {
+		return sql.ErrNoRows
+	}
+
+	query := `UPDATE achievement_references SET status = 'deleted', updated_at = NOW() WHERE id = $1`
+	if _, err := tx.Exec(query, id); err != nil {
+		return err
+	}
+
+	query = `
+		INSERT INTO achievement_status_history (achievement_reference_id, from_status, to_status, note)
+		VALUES ($1, $2, 'deleted', $3)
+	`
+	if _, err := tx.Exec(query, id, fromStatus, note); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func GetAchievementStats(db *sql.DB) (int, int, error) {
 	query := `
 		SELECT 
@@ -226,3 +524,173 @@ func GetAchievementStats(db *sql.DB) (int, int, error) {
 	return total, verified, nil
 }
 
+
+// achievementReferenceConditions menyusun kondisi WHERE filter reference. Query pemanggil harus
+// memakai alias ar untuk achievement_references dan s untuk students pemilik.
+func achievementReferenceConditions(filter model.AchievementReferenceFilter) ([]string, []interface{}) {
+	conditions := []string{"ar.status != 'deleted'"}
+	args := []interface{}{}
+
+	// Prestasi tim muncul untuk setiap peserta dan dosen wali masing-masing peserta.
+	studentCondition := func(placeholder int) string {
+		return fmt.Sprintf(`(ar.student_id = $%[1]d OR EXISTS (
+			SELECT 1 FROM achievement_participants ap
+			WHERE ap.achievement_reference_id = ar.id AND ap.student_id = $%[1]d))`, placeholder)
+	}
+
+	if filter.ScopeStudentID != "" {
+		args = append(args, filter.ScopeStudentID)
+		conditions = append(conditions, studentCondition(len(args)))
+	}
+	if filter.AdvisorID != "" {
+		args = append(args, filter.AdvisorID)
+		conditions = append(conditions, fmt.Sprintf(`(s.advisor_id = $%[1]d OR EXISTS (
+			SELECT 1 FROM achievement_participants ap
+			INNER JOIN students ps ON ap.student_id = ps.id
+			WHERE ap.achievement_reference_id = ar.id AND ps.advisor_id = $%[1]d))`, len(args)))
+	}
+	if filter.StudentID != "" {
+		args = append(args, filter.StudentID)
+		conditions = append(conditions, studentCondition(len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("ar.status = $%d", len(args)))
+	}
+
+	return conditions, args
+}
+
+// GetAchievementReferencePage mengambil satu halaman reference yang cocok dengan filter, urut dari
+// yang terbaru, beserta jumlah seluruh reference yang cocok.
+func GetAchievementReferencePage(db *sql.DB, filter model.AchievementReferenceFilter, offset int, limit int) ([]model.AchievementReference, int, error) {
+	conditions, args := achievementReferenceConditions(filter)
+
+	countQuery := `
+		SELECT COUNT(*)
+		FROM achievement_references ar
+		INNER JOIN students s ON ar.student_id = s.id
+		WHERE ` + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := db.QueryRow(countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, limit, offset)
+	query := `
+		SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status, ar.submitted_at,
+		       ar.verified_at, ar.verified_by, ar.rejection_note, ar.created_at, ar.updated_at
+		FROM achievement_references ar
+		INNER JOIN students s ON ar.student_id = s.id
+		WHERE ` + strings.Join(conditions, " AND ") + fmt.Sprintf(`
+		ORDER BY ar.created_at DESC, ar.id DESC
+		LIMIT $%d OFFSET $%d
+	`, len(args)-1, len(args))
+
+	references, err := queryAchievementReferences(db, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return references, total, nil
+}
+
+// GetAchievementReferenceBatch mengambil paling banyak limit reference yang cocok dengan filter, urut
+// dari yang terbaru. Batch berikutnya dimulai setelah reference after (nil untuk batch pertama).
+func GetAchievementReferenceBatch(db *sql.DB, filter model.AchievementReferenceFilter, after *model.AchievementReference, limit int) ([]model.AchievementReference, error) {
+	conditions, args := achievementReferenceConditions(filter)
+	if after != nil {
+		args = append(args, after.CreatedAt, after.ID)
+		conditions = append(conditions, fmt.Sprintf("(ar.created_at, ar.id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+	args = append(args, limit)
+
+	query := `
+		SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status, ar.submitted_at,
+		       ar.verified_at, ar.verified_by, ar.rejection_note, ar.created_at, ar.updated_at
+		FROM achievement_references ar
+		INNER JOIN students s ON ar.student_id = s.id
+		WHERE ` + strings.Join(conditions, " AND ") + fmt.Sprintf(`
+		ORDER BY ar.created_at DESC, ar.id DESC
+		LIMIT $%d
+	`, len(args))
+
+	return queryAchievementReferences(db, query, args...)
+}
+
+func queryAchievementReferences(db *sql.DB, query string, args ...interface{}) ([]model.AchievementReference, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	references := []model.AchievementReference{}
+	for rows.Next() {
+		var ref model.AchievementReference
+		err := rows.Scan(
+			&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status,
+			&ref.SubmittedAt, &ref.VerifiedAt, &ref.VerifiedBy, &ref.RejectionNote,
+			&ref.CreatedAt, &ref.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		references = append(references, ref)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return references, nil
+}
+
+// GetAchievementExportReferences mengambil paling banyak limit reference untuk export beserta data
+// mahasiswa dan verifikatornya, urut dari yang terbaru. Halaman berikutnya dimulai setelah reference
+// after (nil untuk halaman pertama) dengan keyset pagination, sehingga export besar dibaca per batch.
+func GetAchievementExportReferences(db *sql.DB, filter model.AchievementReferenceFilter, after *model.AchievementExportReference, limit int) ([]model.AchievementExportReference, error) {
+	conditions, args := achievementReferenceConditions(filter)
+	if after != nil {
+		args = append(args, after.CreatedAt, after.ID)
+		conditions = append(conditions, fmt.Sprintf("(ar.created_at, ar.id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+	args = append(args, limit)
+
+	query := `
+		SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status, ar.submitted_at,
+		       ar.verified_at, ar.verified_by, ar.rejection_note, ar.created_at, ar.updated_at,
+		       s.student_id, u.full_name, COALESCE(s.program_study, ''), COALESCE(v.full_name, '')
+		FROM achievement_references ar
+		INNER JOIN students s ON ar.student_id = s.id
+		INNER JOIN users u ON s.user_id = u.id
+		LEFT JOIN users v ON ar.verified_by = v.id
+		WHERE ` + strings.Join(conditions, " AND ") + fmt.Sprintf(`
+		ORDER BY ar.created_at DESC, ar.id DESC
+		LIMIT $%d
+	`, len(args))
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	references := []model.AchievementExportReference{}
+	for rows.Next() {
+		var ref model.AchievementExportReference
+		err := rows.Scan(
+			&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status,
+			&ref.SubmittedAt, &ref.VerifiedAt, &ref.VerifiedBy, &ref.RejectionNote,
+			&ref.CreatedAt, &ref.UpdatedAt,
+			&ref.StudentNumber, &ref.StudentName, &ref.ProgramStudy, &ref.VerifierName,
+		)
+		if err != nil {
+			return nil, err
+		}
+		references = append(references, ref)
+	}
+
+	return references, rows.Err()
+}
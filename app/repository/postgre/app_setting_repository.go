@@ -0,0 +1,22 @@
+package repository
+
+import "database/sql"
+
+func GetAppSetting(db *sql.DB, key string) (string, error) {
+	query := `SELECT value FROM app_settings WHERE key = $1`
+	var value string
+	err := db.QueryRow(query, key).Scan(&value)
+	if err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+func SetAppSetting(db *sql.DB, key string, value string) error {
+	query := `
+		INSERT INTO app_settings (key, value) VALUES ($1, $2)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value
+	`
+	_, err := db.Exec(query, key, value)
+	return err
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	model "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	"strings"
+)
+
+func GetLecturerByID(db *sql.DB, id string) (*model.Lecturer, error) {
+	query := `
+		SELECT l.id, l.user_id, l.lecturer_id, COALESCE(l.department, ''), l.created_at
+		FROM lecturers l
+		WHERE l.id = $1
+	`
+
+	lecturer := new(model.Lecturer)
+	err := db.QueryRow(query, id).Scan(
+		&lecturer.ID, &lecturer.UserID, &lecturer.LecturerID,
+		&lecturer.Department, &lecturer.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return lecturer, nil
+}
+
+func GetAllLecturers(db *sql.DB, filter model.LecturerFilter) ([]model.Lecturer, int, error) {
+	conditions := []string{"1 = 1"}
+	args := []interface{}{}
+
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		conditions = append(conditions, fmt.Sprintf("(l.lecturer_id ILIKE $%d OR l.department ILIKE $%d OR u.full_name ILIKE $%d)", len(args), len(args), len(args)))
+	}
+
+	whereClause := strings.Join(conditions, " AND ")
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM lecturers l INNER JOIN users u ON l.user_id = u.id WHERE ` + whereClause
+	if err := db.QueryRow(countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`
+		SELECT l.id, l.user_id, l.lecturer_id, COALESCE(l.department, ''), l.created_at
+		FROM lecturers l
+		INNER JOIN users u ON l.user_id = u.id
+		WHERE %s
+		ORDER BY l.lecturer_id ASC
+		LIMIT $%d OFFSET $%d
+	`, whereClause, len(args)-1, len(args))
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	lecturers := []model.Lecturer{}
+	for rows.Next() {
+		var lecturer model.Lecturer
+		err := rows.Scan(
+			&lecturer.ID, &lecturer.UserID, &lecturer.LecturerID,
+			&lecturer.Department, &lecturer.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		lecturers = append(lecturers, lecturer)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return lecturers, total, nil
+}
+
+func CreateLecturer(db *sql.DB, req model.CreateLecturerRequest) (*model.Lecturer, error) {
+	query := `
+		INSERT INTO lecturers (user_id, lecturer_id, department)
+		VALUES ($1, $2, NULLIF($3, ''))
+		RETURNING id, user_id, lecturer_id, COALESCE(department, ''), created_at
+	`
+
+	lecturer := new(model.Lecturer)
+	err := db.QueryRow(query, req.UserID, req.LecturerID, req.Department).Scan(
+		&lecturer.ID, &lecturer.UserID, &lecturer.LecturerID,
+		&lecturer.Department, &lecturer.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return lecturer, nil
+}
+
+func UpdateLecturer(db *sql.DB, id string, req model.UpdateLecturerRequest) (*model.Lecturer, error) {
+	query := `
+		UPDATE lecturers
+		SET lecturer_id = $1, department = NULLIF($2, '')
+		WHERE id = $3
+		RETURNING id, user_id, lecturer_id, COALESCE(department, ''), created_at
+	`
+
+	lecturer := new(model.Lecturer)
+	err := db.QueryRow(query, req.LecturerID, req.Department, id).Scan(
+		&lecturer.ID, &lecturer.UserID, &lecturer.LecturerID,
+		&lecturer.Department, &lecturer.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return lecturer, nil
+}
+
+func DeleteLecturer(db *sql.DB, id string) error {
+	query := `DELETE FROM lecturers WHERE id = $1`
+	result, err := db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"database/sql"
+	model "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+)
+
+func GetAllPermissions(db *sql.DB) ([]model.Permission, error) {
+	query := `
+		SELECT p.id, p.name, p.resource, p.action, COALESCE(p.description, '')
+		FROM permissions p
+		ORDER BY p.name ASC
+	`
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := []model.Permission{}
+	for rows.Next() {
+		var permission model.Permission
+		err := rows.Scan(
+			&permission.ID, &permission.Name, &permission.Resource,
+			&permission.Action, &permission.Description,
+		)
+		if err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+func GetPermissionByID(db *sql.DB, id string) (*model.Permission, error) {
+	query := `
+		SELECT p.id, p.name, p.resource, p.action, COALESCE(p.description, '')
+		FROM permissions p
+		WHERE p.id = $1
+	`
+
+	permission := new(model.Permission)
+	err := db.QueryRow(query, id).Scan(
+		&permission.ID, &permission.Name, &permission.Resource,
+		&permission.Action, &permission.Description,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return permission, nil
+}
+
+func CreatePermission(db *sql.DB, req model.CreatePermissionRequest) (*model.Permission, error) {
+	query := `
+		INSERT INTO permissions (name, resource, action, description)
+		VALUES ($1, $2, $3, NULLIF($4, ''))
+		RETURNING id, name, resource, action, COALESCE(description, '')
+	`
+
+	permission := new(model.Permission)
+	err := db.QueryRow(query, req.Name, req.Resource, req.Action, req.Description).Scan(
+		&permission.ID, &permission.Name, &permission.Resource,
+		&permission.Action, &permission.Description,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return permission, nil
+}
+
+func UpdatePermission(db *sql.DB, id string, req model.UpdatePermissionRequest) (*model.Permission, error) {
+	query := `
+		UPDATE permissions
+		SET name = $1, resource = $2, action = $3, description = NULLIF($4, '')
+		WHERE id = $5
+		RETURNING id, name, resource, action, COALESCE(description, '')
+	`
+
+	permission := new(model.Permission)
+	err := db.QueryRow(query, req.Name, req.Resource, req.Action, req.Description, id).Scan(
+		&permission.ID, &permission.Name, &permission.Resource,
+		&permission.Action, &permission.Description,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return permission, nil
+}
+
+func DeletePermission(db *sql.DB, id string) error {
+	query := `DELETE FROM permissions WHERE id = $1`
+	result, err := db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	model "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+)
+
+const pointRubricColumns = `id, version, status, rules, team_split_rules, created_by, published_at, created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPointRubric(row rowScanner) (*model.PointRubric, error) {
+	var rubric model.PointRubric
+	var rules, teamSplitRules []byte
+	err := row.Scan(
+		&rubric.ID, &rubric.Version, &rubric.Status, &rules, &teamSplitRules, &rubric.CreatedBy,
+		&rubric.PublishedAt, &rubric.CreatedAt, &rubric.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rubric.Rules = []model.PointRule{}
+	if err := json.Unmarshal(rules, &rubric.Rules); err != nil {
+		return nil, err
+	}
+
+	rubric.TeamSplitRules = []model.TeamSplitRule{}
+	if err := json.Unmarshal(teamSplitRules, &rubric.TeamSplitRules); err != nil {
+		return nil, err
+	}
+
+	return &rubric, nil
+}
+
+func GetAllPointRubrics(db *sql.DB) ([]model.PointRubric, error) {
+	query := `SELECT ` + pointRubricColumns + ` FROM point_rubrics ORDER BY version DESC`
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rubrics := []model.PointRubric{}
+	for rows.Next() {
+		rubric, err := scanPointRubric(rows)
+		if err != nil {
+			return nil, err
+		}
+		rubrics = append(rubrics, *rubric)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rubrics, nil
+}
+
+func GetPointRubricByStatus(db *sql.DB, status string) (*model.PointRubric, error) {
+	query := `SELECT ` + pointRubricColumns + ` FROM point_rubrics WHERE status = $1`
+	return scanPointRubric(db.QueryRow(query, status))
+}
+
+// SaveDraftPointRubric mengganti rules dan aturan pembagian poin tim pada draft yang ada, atau
+// membuat draft baru dengan versi berikutnya jika belum ada draft.
+func SaveDraftPointRubric(db *sql.DB, rules []model.PointRule, teamSplitRules []model.TeamSplitRule, createdBy string) (*model.PointRubric, error) {
+	payload, err := json.Marshal(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	if teamSplitRules == nil {
+		teamSplitRules = []model.TeamSplitRule{}
+	}
+	teamSplitPayload, err := json.Marshal(teamSplitRules)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE point_rubrics
+		SET rules = $1, team_split_rules = $2, created_by = $3
+		WHERE status = 'draft'
+		RETURNING ` + pointRubricColumns
+
+	rubric, err := scanPointRubric(tx.QueryRow(query, payload, teamSplitPayload, createdBy))
+	if err == sql.ErrNoRows {
+		// Kunci tabel agar dua admin tidak membuat draft dengan versi yang sama.
+		if _, err := tx.Exec(`LOCK TABLE point_rubrics IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+			return nil, err
+		}
+
+		query = `
+			INSERT INTO point_rubrics (version, status, rules, team_split_rules, created_by)
+			SELECT COALESCE(MAX(version), 0) + 1, 'draft', $1, $2, $3 FROM point_rubrics
+			RETURNING ` + pointRubricColumns
+		rubric, err = scanPointRubric(tx.QueryRow(query, payload, teamSplitPayload, createdBy))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return rubric, nil
+}
+
+func DeleteDraftPointRubric(db *sql.DB) error {
+	query := `DELETE FROM point_rubrics WHERE status = 'draft'`
+	result, err := db.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// PublishDraftPointRubric menerbitkan draft dan mengarsipkan rubrik yang sebelumnya aktif.
+func PublishDraftPointRubric(db *sql.DB) (*model.PointRubric, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var draftID string
+	err = tx.QueryRow(`SELECT id FROM point_rubrics WHERE status = 'draft' FOR UPDATE`).Scan(&draftID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(`UPDATE point_rubrics SET status = 'archived' WHERE status = 'published'`); err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE point_rubrics
+		SET status = 'published', published_at = NOW()
+		WHERE id = $1
+		RETURNING ` + pointRubricColumns
+
+	rubric, err := scanPointRubric(tx.QueryRow(query, draftID))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return rubric, nil
+}
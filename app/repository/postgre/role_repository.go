@@ -0,0 +1,317 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	model "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+
+	"github.com/lib/pq"
+)
+
+// ErrLastPermissionHolder dikembalikan ketika perubahan akan menghilangkan user aktif terakhir
+// yang memiliki permission yang dilindungi.
+var ErrLastPermissionHolder = errors.New("tidak ada user aktif lain yang memiliki permission ini")
+
+func GetAllRoles(db *sql.DB) ([]model.Role, error) {
+	query := `
+		SELECT r.id, r.name, COALESCE(r.description, ''), r.created_at
+		FROM roles r
+		ORDER BY r.name ASC
+	`
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []model.Role{}
+	for rows.Next() {
+		var role model.Role
+		if err := rows.Scan(&role.ID, &role.Name, &role.Description, &role.CreatedAt); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func GetRoleByID(db *sql.DB, id string) (*model.Role, error) {
+	query := `
+		SELECT r.id, r.name, COALESCE(r.description, ''), r.created_at
+		FROM roles r
+		WHERE r.id = $1
+	`
+
+	role := new(model.Role)
+	err := db.QueryRow(query, id).Scan(
+		&role.ID, &role.Name, &role.Description, &role.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+func CreateRole(db *sql.DB, req model.CreateRoleRequest) (*model.Role, error) {
+	query := `
+		INSERT INTO roles (name, description)
+		VALUES ($1, NULLIF($2, ''))
+		RETURNING id, name, COALESCE(description, ''), created_at
+	`
+
+	role := new(model.Role)
+	err := db.QueryRow(query, req.Name, req.Description).Scan(
+		&role.ID, &role.Name, &role.Description, &role.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+func UpdateRole(db *sql.DB, id string, req model.UpdateRoleRequest) (*model.Role, error) {
+	query := `
+		UPDATE roles
+		SET name = $1, description = NULLIF($2, '')
+		WHERE id = $3
+		RETURNING id, name, COALESCE(description, ''), created_at
+	`
+
+	role := new(model.Role)
+	err := db.QueryRow(query, req.Name, req.Description, id).Scan(
+		&role.ID, &role.Name, &role.Description, &role.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+// DeleteRole menghapus role. Penghapusan ditolak dengan ErrLastPermissionHolder jika role ini
+// memiliki protectedPermission dan tidak ada user aktif di role lain yang memilikinya.
+func DeleteRole(db *sql.DB, id string, protectedPermission string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	holders, err := lockPermissionHolders(tx, protectedPermission)
+	if err != nil {
+		return err
+	}
+
+	if err := ensureHolderOutsideRole(tx, id, holders); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`DELETE FROM roles WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+// GetRoleIDsByPermission mengembalikan ID semua role yang memiliki permission bernama permissionName.
+func GetRoleIDsByPermission(db *sql.DB, permissionName string) ([]string, error) {
+	query := `
+		SELECT rp.role_id
+		FROM role_permissions rp
+		INNER JOIN permissions p ON rp.permission_id = p.id
+		WHERE p.name = $1
+		ORDER BY rp.role_id
+	`
+
+	rows, err := db.Query(query, permissionName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roleIDs := []string{}
+	for rows.Next() {
+		var roleID string
+		if err := rows.Scan(&roleID); err != nil {
+			return nil, err
+		}
+		roleIDs = append(roleIDs, roleID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return roleIDs, nil
+}
+
+func GetRolePermissions(db *sql.DB, roleID string) ([]model.Permission, error) {
+	query := `
+		SELECT p.id, p.name, p.resource, p.action, COALESCE(p.description, '')
+		FROM role_permissions rp
+		INNER JOIN permissions p ON rp.permission_id = p.id
+		WHERE rp.role_id = $1
+		ORDER BY p.name ASC
+	`
+
+	rows, err := db.Query(query, roleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := []model.Permission{}
+	for rows.Next() {
+		var permission model.Permission
+		err := rows.Scan(
+			&permission.ID, &permission.Name, &permission.Resource,
+			&permission.Action, &permission.Description,
+		)
+		if err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+func GrantRolePermission(db *sql.DB, req model.CreateRolePermissionRequest) (*model.RolePermission, error) {
+	query := `
+		INSERT INTO role_permissions (role_id, permission_id)
+		VALUES ($1, $2)
+		RETURNING role_id, permission_id
+	`
+
+	rolePermission := new(model.RolePermission)
+	err := db.QueryRow(query, req.RoleID, req.PermissionID).Scan(
+		&rolePermission.RoleID, &rolePermission.PermissionID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rolePermission, nil
+}
+
+// RevokeRolePermission mencabut permission dari role. Jika permission tersebut adalah
+// protectedPermission, pencabutan ditolak ketika tidak ada user aktif di role lain yang memilikinya.
+func RevokeRolePermission(db *sql.DB, roleID string, permissionID string, protectedPermission string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var permissionName string
+	err = tx.QueryRow(`SELECT name FROM permissions WHERE id = $1`, permissionID).Scan(&permissionName)
+	if err != nil {
+		return err
+	}
+
+	if permissionName == protectedPermission {
+		holders, err := lockPermissionHolders(tx, protectedPermission)
+		if err != nil {
+			return err
+		}
+
+		if err := ensureHolderOutsideRole(tx, roleID, holders); err != nil {
+			return err
+		}
+	}
+
+	result, err := tx.Exec(`DELETE FROM role_permissions WHERE role_id = $1 AND permission_id = $2`, roleID, permissionID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+// ensureHolderOutsideRole mengembalikan ErrLastPermissionHolder jika roleID termasuk holders dan
+// tidak ada user aktif di role lain dari holders. Sama seperti pemeriksaan pada UpdateUser, yang
+// dihitung adalah user aktif, bukan role, karena role tanpa user tidak dapat dipakai untuk login.
+func ensureHolderOutsideRole(tx *sql.Tx, roleID string, holders []string) error {
+	if !containsString(holders, roleID) {
+		return nil
+	}
+
+	var otherHolders int
+	err := tx.QueryRow(
+		`SELECT COUNT(*) FROM users WHERE is_active = true AND role_id <> $1 AND role_id = ANY($2)`,
+		roleID, pq.Array(holders),
+	).Scan(&otherHolders)
+	if err != nil {
+		return err
+	}
+	if otherHolders == 0 {
+		return ErrLastPermissionHolder
+	}
+	return nil
+}
+
+func lockPermissionHolders(tx *sql.Tx, permissionName string) ([]string, error) {
+	query := `
+		SELECT rp.role_id
+		FROM role_permissions rp
+		INNER JOIN permissions p ON rp.permission_id = p.id
+		WHERE p.name = $1
+		FOR UPDATE OF rp
+	`
+
+	rows, err := tx.Query(query, permissionName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roleIDs []string
+	for rows.Next() {
+		var roleID string
+		if err := rows.Scan(&roleID); err != nil {
+			return nil, err
+		}
+		roleIDs = append(roleIDs, roleID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return roleIDs, nil
+}
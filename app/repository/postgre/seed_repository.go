@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	model "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	"time"
+)
+
+// SeedUser membuat user dengan role roleName jika username belum ada. Mengembalikan ID user dan
+// apakah user baru dibuat.
+func SeedUser(db *sql.DB, username string, email string, passwordHash string, fullName string, roleName string) (string, bool, error) {
+	query := `
+		INSERT INTO users (username, email, password_hash, full_name, role_id, is_active)
+		SELECT $1, $2, $3, $4, id, true FROM roles WHERE name = $5
+		ON CONFLICT DO NOTHING
+		RETURNING id
+	`
+	var id string
+	err := db.QueryRow(query, username, email, passwordHash, fullName, roleName).Scan(&id)
+	if err == nil {
+		return id, true, nil
+	}
+	if err != sql.ErrNoRows {
+		return "", false, err
+	}
+
+	err = db.QueryRow(`SELECT id FROM users WHERE username = $1`, username).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", false, fmt.Errorf("cannot seed user %s: role %s not found or email already used", username, roleName)
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return id, false, nil
+}
+
+// SeedLecturer membuat profil dosen untuk userID jika belum ada dan mengembalikan ID-nya.
+func SeedLecturer(db *sql.DB, userID string, lecturerID string, department string) (string, bool, error) {
+	query := `
+		INSERT INTO lecturers (user_id, lecturer_id, department)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+		RETURNING id
+	`
+	var id string
+	err := db.QueryRow(query, userID, lecturerID, department).Scan(&id)
+	if err == nil {
+		return id, true, nil
+	}
+	if err != sql.ErrNoRows {
+		return "", false, err
+	}
+
+	err = db.QueryRow(`SELECT id FROM lecturers WHERE user_id = $1`, userID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", false, fmt.Errorf("cannot seed lecturer %s: lecturer ID already used", lecturerID)
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return id, false, nil
+}
+
+// SeedStudent membuat profil mahasiswa untuk userID jika belum ada dan mengembalikan ID-nya.
+func SeedStudent(db *sql.DB, userID string, studentID string, programStudy string, academicYear string, advisorID *string) (string, bool, error) {
+	query := `
+		INSERT INTO students (user_id, student_id, program_study, academic_year, advisor_id)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT DO NOTHING
+		RETURNING id
+	`
+	var id string
+	err := db.QueryRow(query, userID, studentID, programStudy, academicYear, advisorID).Scan(&id)
+	if err == nil {
+		return id, true, nil
+	}
+	if err != sql.ErrNoRows {
+		return "", false, err
+	}
+
+	err = db.QueryRow(`SELECT id FROM students WHERE user_id = $1`, userID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", false, fmt.Errorf("cannot seed student %s: student ID already used", studentID)
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return id, false, nil
+}
+
+// CreateSeedAchievementReferences membuat reference beserta riwayat status lengkap sesuai
+// workflow (draft, submitted, lalu verified/rejected) dalam satu transaksi.
+func CreateSeedAchievementReferences(db *sql.DB, refs []model.SeedAchievementReference) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO achievement_references (student_id, mongo_achievement_id, status, submitted_at,
+		                                    verified_at, verified_by, rejection_note, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`
+	historyQuery := `
+		INSERT INTO achievement_status_history (achievement_reference_id, from_status, to_status, changed_by, note, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	for _, ref := range refs {
+		var submittedAt, verifiedAt *time.Time
+		var verifiedBy *string
+		updatedAt := ref.CreatedAt
+		if ref.Status != model.AchievementStatusDraft {
+			submitted := ref.CreatedAt.Add(24 * time.Hour)
+			submittedAt = &submitted
+			updatedAt = submitted
+		}
+		if ref.Status == model.AchievementStatusVerified || ref.Status == model.AchievementStatusRejected {
+			verified := ref.CreatedAt.Add(72 * time.Hour)
+			verifiedAt = &verified
+			verifiedBy = ref.AdvisorUserID
+			updatedAt = verified
+		}
+
+		var id string
+		err := tx.QueryRow(query, ref.StudentID, ref.MongoAchievementID, ref.Status, submittedAt,
+			verifiedAt, verifiedBy, ref.RejectionNote, ref.CreatedAt, updatedAt).Scan(&id)
+		if err != nil {
+			return err
+		}
+
+		draft := model.AchievementStatusDraft
+		submitted := model.AchievementStatusSubmitted
+		if _, err := tx.Exec(historyQuery, id, nil, draft, ref.StudentUserID, nil, ref.CreatedAt); err != nil {
+			return err
+		}
+		if submittedAt != nil {
+			if _, err := tx.Exec(historyQuery, id, draft, submitted, ref.StudentUserID, nil, *submittedAt); err != nil {
+				return err
+			}
+		}
+		if verifiedAt != nil {
+			if _, err := tx.Exec(historyQuery, id, submitted, ref.Status, ref.AdvisorUserID, ref.RejectionNote, *verifiedAt); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
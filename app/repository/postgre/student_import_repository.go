@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"database/sql"
+	model "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+
+	"github.com/lib/pq"
+)
+
+// GetStudentAccountsByStudentIDs mengambil akun mahasiswa berdasarkan NIM, dipetakan per NIM.
+func GetStudentAccountsByStudentIDs(db *sql.DB, studentIDs []string) (map[string]model.StudentAccount, error) {
+	accounts := make(map[string]model.StudentAccount, len(studentIDs))
+	if len(studentIDs) == 0 {
+		return accounts, nil
+	}
+
+	query := `
+		SELECT s.id, s.user_id, s.student_id, u.username, u.email, u.full_name,
+		       COALESCE(s.program_study, ''), COALESCE(s.academic_year, ''), COALESCE(s.advisor_id::text, '')
+		FROM students s
+		INNER JOIN users u ON s.user_id = u.id
+		WHERE s.student_id = ANY($1)
+	`
+
+	rows, err := db.Query(query, pq.Array(studentIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var account model.StudentAccount
+		err := rows.Scan(
+			&account.ID, &account.UserID, &account.StudentID, &account.Username, &account.Email,
+			&account.FullName, &account.ProgramStudy, &account.AcademicYear, &account.AdvisorID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		accounts[account.StudentID] = account
+	}
+
+	return accounts, rows.Err()
+}
+
+// GetLecturerIDsByNIPs memetakan NIP (lecturers.lecturer_id) ke ID dosen. NIP yang tidak
+// ditemukan tidak ada di map hasil.
+func GetLecturerIDsByNIPs(db *sql.DB, nips []string) (map[string]string, error) {
+	ids := make(map[string]string, len(nips))
+	if len(nips) == 0 {
+		return ids, nil
+	}
+
+	rows, err := db.Query(`SELECT lecturer_id, id FROM lecturers WHERE lecturer_id = ANY($1)`, pq.Array(nips))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var nip, id string
+		if err := rows.Scan(&nip, &id); err != nil {
+			return nil, err
+		}
+		ids[nip] = id
+	}
+
+	return ids, rows.Err()
+}
+
+// CreateStudentAccount membuat user dengan role roleID beserta profil mahasiswanya dalam satu
+// transaksi.
+func CreateStudentAccount(db *sql.DB, account model.StudentAccount, roleID string, passwordHash string) (*model.StudentAccount, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		INSERT INTO users (username, email, password_hash, full_name, role_id, is_active)
+		VALUES ($1, $2, $3, $4, $5, true)
+		RETURNING id
+	`, account.Username, account.Email, passwordHash, account.FullName, roleID).Scan(&account.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO students (user_id, student_id, program_study, academic_year, advisor_id)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, '')::uuid)
+		RETURNING id
+	`, account.UserID, account.StudentID, account.ProgramStudy, account.AcademicYear, account.AdvisorID).Scan(&account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+// UpdateStudentAccount memperbarui data user dan profil mahasiswa yang sudah ada dalam satu
+// transaksi. Password tidak diubah.
+func UpdateStudentAccount(db *sql.DB, account model.StudentAccount) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE users SET username = $1, email = $2, full_name = $3, updated_at = NOW()
+		WHERE id = $4
+	`, account.Username, account.Email, account.FullName, account.UserID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE students
+		SET program_study = NULLIF($1, ''), academic_year = NULLIF($2, ''), advisor_id = NULLIF($3, '')::uuid
+		WHERE id = $4
+	`, account.ProgramStudy, account.AcademicYear, account.AdvisorID, account.ID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
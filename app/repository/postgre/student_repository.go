@@ -2,7 +2,11 @@ package repository
 
 import (
 	"database/sql"
+	"fmt"
 	model "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	"strings"
+
+	"github.com/lib/pq"
 )
 
 func GetStudentIDByUserID(db *sql.DB, userID string) (string, error) {
@@ -23,8 +27,8 @@ func GetStudentIDByUserID(db *sql.DB, userID string) (string, error) {
 
 func GetStudentByUserID(db *sql.DB, userID string) (*model.Student, error) {
 	query := `
-		SELECT s.id, s.user_id, s.student_id, s.program_study, 
-		       s.academic_year, s.advisor_id, s.created_at
+		SELECT s.id, s.user_id, s.student_id, COALESCE(s.program_study, ''),
+		       COALESCE(s.academic_year, ''), COALESCE(s.advisor_id::text, ''), s.created_at
 		FROM students s
 		WHERE s.user_id = $1
 	`
@@ -45,8 +49,8 @@ func GetStudentByUserID(db *sql.DB, userID string) (*model.Student, error) {
 
 func GetStudentsByAdvisorID(db *sql.DB, advisorID string) ([]model.Student, error) {
 	query := `
-		SELECT s.id, s.user_id, s.student_id, s.program_study, 
-		       s.academic_year, s.advisor_id, s.created_at
+		SELECT s.id, s.user_id, s.student_id, COALESCE(s.program_study, ''),
+		       COALESCE(s.academic_year, ''), COALESCE(s.advisor_id::text, ''), s.created_at
 		FROM students s
 		WHERE s.advisor_id = $1
 		ORDER BY s.created_at DESC
@@ -58,7 +62,7 @@ func GetStudentsByAdvisorID(db *sql.DB, advisorID string) ([]model.Student, erro
 	}
 	defer rows.Close()
 
-	var students []model.Student
+	students := []model.Student{}
 	for rows.Next() {
 		var student model.Student
 		err := rows.Scan(
@@ -79,3 +83,187 @@ func GetStudentsByAdvisorID(db *sql.DB, advisorID string) ([]model.Student, erro
 	return students, nil
 }
 
+func GetStudentByID(db *sql.DB, id string) (*model.Student, error) {
+	query := `
+		SELECT s.id, s.user_id, s.student_id, COALESCE(s.program_study, ''),
+		       COALESCE(s.academic_year, ''), COALESCE(s.advisor_id::text, ''), s.created_at
+		FROM students s
+		WHERE s.id = $1
+	`
+
+	student := new(model.Student)
+	err := db.QueryRow(query, id).Scan(
+		&student.ID, &student.UserID, &student.StudentID,
+		&student.ProgramStudy, &student.AcademicYear, &student.AdvisorID,
+		&student.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return student, nil
+}
+
+func GetAllStudents(db *sql.DB, filter model.StudentFilter) ([]model.Student, int, error) {
+	conditions := []string{"1 = 1"}
+	args := []interface{}{}
+
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		conditions = append(conditions, fmt.Sprintf("(s.student_id ILIKE $%d OR s.program_study ILIKE $%d OR u.full_name ILIKE $%d)", len(args), len(args), len(args)))
+	}
+	if filter.AdvisorID != "" {
+		args = append(args, filter.AdvisorID)
+		conditions = append(conditions, fmt.Sprintf("s.advisor_id = $%d", len(args)))
+	}
+	if filter.AcademicYear != "" {
+		args = append(args, filter.AcademicYear)
+		conditions = append(conditions, fmt.Sprintf("s.academic_year = $%d", len(args)))
+	}
+
+	whereClause := strings.Join(conditions, " AND ")
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM students s INNER JOIN users u ON s.user_id = u.id WHERE ` + whereClause
+	if err := db.QueryRow(countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`
+		SELECT s.id, s.user_id, s.student_id, COALESCE(s.program_study, ''),
+		       COALESCE(s.academic_year, ''), COALESCE(s.advisor_id::text, ''), s.created_at
+		FROM students s
+		INNER JOIN users u ON s.user_id = u.id
+		WHERE %s
+		ORDER BY s.student_id ASC
+		LIMIT $%d OFFSET $%d
+	`, whereClause, len(args)-1, len(args))
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	students := []model.Student{}
+	for rows.Next() {
+		var student model.Student
+		err := rows.Scan(
+			&student.ID, &student.UserID, &student.StudentID,
+			&student.ProgramStudy, &student.AcademicYear, &student.AdvisorID,
+			&student.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		students = append(students, student)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return students, total, nil
+}
+
+func CreateStudent(db *sql.DB, req model.CreateStudentRequest) (*model.Student, error) {
+	query := `
+		INSERT INTO students (user_id, student_id, program_study, academic_year, advisor_id)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, '')::uuid)
+		RETURNING id, user_id, student_id, COALESCE(program_study, ''),
+		          COALESCE(academic_year, ''), COALESCE(advisor_id::text, ''), created_at
+	`
+
+	student := new(model.Student)
+	err := db.QueryRow(query, req.UserID, req.StudentID, req.ProgramStudy, req.AcademicYear, req.AdvisorID).Scan(
+		&student.ID, &student.UserID, &student.StudentID,
+		&student.ProgramStudy, &student.AcademicYear, &student.AdvisorID,
+		&student.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return student, nil
+}
+
+func UpdateStudent(db *sql.DB, id string, req model.UpdateStudentRequest) (*model.Student, error) {
+	query := `
+		UPDATE students
+		SET student_id = $1, program_study = NULLIF($2, ''), academic_year = NULLIF($3, ''),
+		    advisor_id = NULLIF($4, '')::uuid
+		WHERE id = $5
+		RETURNING id, user_id, student_id, COALESCE(program_study, ''),
+		          COALESCE(academic_year, ''), COALESCE(advisor_id::text, ''), created_at
+	`
+
+	student := new(model.Student)
+	err := db.QueryRow(query, req.StudentID, req.ProgramStudy, req.AcademicYear, req.AdvisorID, id).Scan(
+		&student.ID, &student.UserID, &student.StudentID,
+		&student.ProgramStudy, &student.AcademicYear, &student.AdvisorID,
+		&student.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return student, nil
+}
+
+func DeleteStudent(db *sql.DB, id string) error {
+	query := `DELETE FROM students WHERE id = $1`
+	result, err := db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func CountAchievementReferencesByStudentID(db *sql.DB, studentID string) (int, error) {
+	query := `SELECT COUNT(*) FROM achievement_references WHERE student_id = $1 AND status != 'deleted'`
+	var count int
+	err := db.QueryRow(query, studentID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetStudentIDsByStudentNumbers memetakan NIM ke ID mahasiswa. NIM yang tidak ditemukan tidak
+// ada di map hasil.
+func GetStudentIDsByStudentNumbers(db *sql.DB, studentNumbers []string) (map[string]string, error) {
+	ids := make(map[string]string, len(studentNumbers))
+	if len(studentNumbers) == 0 {
+		return ids, nil
+	}
+
+	rows, err := db.Query(`SELECT student_id, id FROM students WHERE student_id = ANY($1)`, pq.Array(studentNumbers))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var studentNumber, id string
+		if err := rows.Scan(&studentNumber, &id); err != nil {
+			return nil, err
+		}
+		ids[studentNumber] = id
+	}
+
+	return ids, rows.Err()
+}
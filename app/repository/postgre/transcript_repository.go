@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"database/sql"
+	model "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+
+	"github.com/lib/pq"
+)
+
+func GetTranscriptStudent(db *sql.DB, studentID string) (*model.TranscriptStudent, error) {
+	query := `
+		SELECT s.id, s.student_id, u.full_name, COALESCE(s.program_study, ''),
+		       COALESCE(s.academic_year, ''), COALESCE(au.full_name, '')
+		FROM students s
+		INNER JOIN users u ON s.user_id = u.id
+		LEFT JOIN lecturers l ON s.advisor_id = l.id
+		LEFT JOIN users au ON l.user_id = au.id
+		WHERE s.id = $1
+	`
+
+	var student model.TranscriptStudent
+	err := db.QueryRow(query, studentID).Scan(
+		&student.ID, &student.StudentNumber, &student.FullName, &student.ProgramStudy,
+		&student.AcademicYear, &student.AdvisorName,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &student, nil
+}
+
+// GetTranscriptEntries mengambil prestasi verified milik mahasiswa, baik sebagai pemilik maupun
+// peserta prestasi tim, urut dari yang paling awal diverifikasi.
+func GetTranscriptEntries(db *sql.DB, studentID string) ([]model.TranscriptEntry, error) {
+	query := `
+		SELECT ar.id, ar.mongo_achievement_id, ar.verified_at, COALESCE(v.full_name, ''),
+		       ap.role::text, ap.points
+		FROM achievement_references ar
+		LEFT JOIN achievement_participants ap ON ap.achievement_reference_id = ar.id AND ap.student_id = $1
+		LEFT JOIN users v ON ar.verified_by = v.id
+		WHERE ar.status = 'verified' AND (ar.student_id = $1 OR ap.id IS NOT NULL)
+		ORDER BY ar.verified_at ASC, ar.id ASC
+	`
+
+	rows, err := db.Query(query, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []model.TranscriptEntry{}
+	for rows.Next() {
+		var entry model.TranscriptEntry
+		err := rows.Scan(
+			&entry.ReferenceID, &entry.MongoAchievementID, &entry.VerifiedAt, &entry.VerifierName,
+			&entry.ParticipantRole, &entry.ParticipantPoints,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// CreateTranscript mencatat transkrip yang diterbitkan. ID diisi pemanggil karena kode verifikasi
+// dibuat dari ID sebelum transkrip disimpan.
+func CreateTranscript(db *sql.DB, transcript model.Transcript) (*model.Transcript, error) {
+	query := `
+		INSERT INTO transcripts (id, student_id, issued_by, achievement_reference_ids, total_points)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING issued_at
+	`
+
+	err := db.QueryRow(
+		query, transcript.ID, transcript.StudentID, transcript.IssuedBy, pq.Array(transcript.AchievementReferenceIDs), transcript.TotalPoints,
+	).Scan(&transcript.IssuedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transcript, nil
+}
+
+const transcriptColumns = `
+	id, student_id, issued_by, achievement_reference_ids, total_points, issued_at,
+	revoked_at, revoked_by, revocation_reason
+`
+
+func scanTranscript(row interface{ Scan(...interface{}) error }) (*model.Transcript, error) {
+	var transcript model.Transcript
+	err := row.Scan(
+		&transcript.ID, &transcript.StudentID, &transcript.IssuedBy, pq.Array(&transcript.AchievementReferenceIDs),
+		&transcript.TotalPoints, &transcript.IssuedAt, &transcript.RevokedAt, &transcript.RevokedBy, &transcript.RevocationReason,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &transcript, nil
+}
+
+func GetTranscriptByID(db *sql.DB, id string) (*model.Transcript, error) {
+	return scanTranscript(db.QueryRow(`SELECT `+transcriptColumns+` FROM transcripts WHERE id = $1`, id))
+}
+
+// GetTranscriptsByStudentID mengambil seluruh transkrip yang pernah diterbitkan untuk mahasiswa,
+// terbaru lebih dulu.
+func GetTranscriptsByStudentID(db *sql.DB, studentID string) ([]model.Transcript, error) {
+	rows, err := db.Query(`SELECT `+transcriptColumns+` FROM transcripts WHERE student_id = $1 ORDER BY issued_at DESC`, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transcripts := []model.Transcript{}
+	for rows.Next() {
+		transcript, err := scanTranscript(rows)
+		if err != nil {
+			return nil, err
+		}
+		transcripts = append(transcripts, *transcript)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return transcripts, nil
+}
+
+// RevokeTranscript mencabut transkrip yang belum dicabut. sql.ErrNoRows berarti transkrip tidak
+// ditemukan atau sudah dicabut sebelumnya.
+func RevokeTranscript(db *sql.DB, id string, revokedBy string, reason string) (*model.Transcript, error) {
+	query := `
+		UPDATE transcripts
+		SET revoked_at = NOW(), revoked_by = $2, revocation_reason = $3
+		WHERE id = $1 AND revoked_at IS NULL
+		RETURNING ` + transcriptColumns
+
+	return scanTranscript(db.QueryRow(query, id, revokedBy, reason))
+}
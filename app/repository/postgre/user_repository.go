@@ -2,9 +2,18 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 	model "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	"strings"
+
+	"github.com/lib/pq"
 )
 
+// ErrUserHasProfile dikembalikan ketika user yang akan dihapus masih memiliki profil mahasiswa
+// atau dosen.
+var ErrUserHasProfile = errors.New("user masih memiliki profil mahasiswa atau dosen")
+
 func GetUserByEmail(db *sql.DB, email string) (*model.User, error) {
 	query := `
 		SELECT u.id, u.username, u.email, u.password_hash, u.full_name, 
@@ -71,6 +80,262 @@ func GetUserByUsernameOrEmail(db *sql.DB, usernameOrEmail string) (*model.User,
 	return user, nil
 }
 
+func GetAllUsers(db *sql.DB, filter model.UserFilter) ([]model.User, int, error) {
+	conditions := []string{"1 = 1"}
+	args := []interface{}{}
+
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		conditions = append(conditions, fmt.Sprintf("(u.username ILIKE $%d OR u.email ILIKE $%d OR u.full_name ILIKE $%d)", len(args), len(args), len(args)))
+	}
+	if filter.RoleID != "" {
+		args = append(args, filter.RoleID)
+		conditions = append(conditions, fmt.Sprintf("u.role_id = $%d", len(args)))
+	}
+	if filter.IsActive != nil {
+		args = append(args, *filter.IsActive)
+		conditions = append(conditions, fmt.Sprintf("u.is_active = $%d", len(args)))
+	}
+
+	whereClause := strings.Join(conditions, " AND ")
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM users u WHERE ` + whereClause
+	if err := db.QueryRow(countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`
+		SELECT u.id, u.username, u.email, u.password_hash, u.full_name, 
+		       u.role_id, u.is_active, u.created_at, u.updated_at
+		FROM users u
+		WHERE %s
+		ORDER BY u.created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, whereClause, len(args)-1, len(args))
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	users := []model.User{}
+	for rows.Next() {
+		var user model.User
+		err := rows.Scan(
+			&user.ID, &user.Username, &user.Email, &user.PasswordHash,
+			&user.FullName, &user.RoleID, &user.IsActive,
+			&user.CreatedAt, &user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+func CreateUser(db *sql.DB, req model.CreateUserRequest, passwordHash string) (*model.User, error) {
+	query := `
+		INSERT INTO users (username, email, password_hash, full_name, role_id, is_active)
+		VALUES ($1, $2, $3, $4, $5, true)
+		RETURNING id, username, email, password_hash, full_name,
+		          role_id, is_active, created_at, updated_at
+	`
+
+	user := new(model.User)
+	err := db.QueryRow(query, req.Username, req.Email, passwordHash, req.FullName, req.RoleID).Scan(
+		&user.ID, &user.Username, &user.Email, &user.PasswordHash,
+		&user.FullName, &user.RoleID, &user.IsActive,
+		&user.CreatedAt, &user.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// UpdateUser memperbarui data user. Perubahan role atau status aktif ditolak dengan
+// ErrLastPermissionHolder jika user ini adalah user aktif terakhir yang memiliki protectedPermission.
+func UpdateUser(db *sql.DB, id string, req model.UpdateUserRequest, protectedPermission string) (*model.User, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	holders, err := lockPermissionHolders(tx, protectedPermission)
+	if err != nil {
+		return nil, err
+	}
+
+	var currentRoleID string
+	var isActive bool
+	err = tx.QueryRow(`SELECT role_id, is_active FROM users WHERE id = $1 FOR UPDATE`, id).Scan(&currentRoleID, &isActive)
+	if err != nil {
+		return nil, err
+	}
+
+	willBeActive := isActive
+	if req.IsActive != nil {
+		willBeActive = *req.IsActive
+	}
+	if isActive && containsString(holders, currentRoleID) && (!willBeActive || !containsString(holders, req.RoleID)) {
+		if err := ensureOtherPermissionHolder(tx, id, holders); err != nil {
+			return nil, err
+		}
+	}
+
+	query := `
+		UPDATE users
+		SET username = $1, email = $2, full_name = $3, role_id = $4,
+		    is_active = COALESCE($5, is_active), updated_at = NOW()
+		WHERE id = $6
+		RETURNING id, username, email, password_hash, full_name,
+		          role_id, is_active, created_at, updated_at
+	`
+
+	user := new(model.User)
+	err = tx.QueryRow(query, req.Username, req.Email, req.FullName, req.RoleID, req.IsActive, id).Scan(
+		&user.ID, &user.Username, &user.Email, &user.PasswordHash,
+		&user.FullName, &user.RoleID, &user.IsActive,
+		&user.CreatedAt, &user.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// lockUserForRemoval mengunci baris user yang akan dinonaktifkan atau dihapus dan mengembalikan
+// ErrLastPermissionHolder jika ia adalah user aktif terakhir yang memiliki protectedPermission.
+func lockUserForRemoval(tx *sql.Tx, id string, protectedPermission string) error {
+	holders, err := lockPermissionHolders(tx, protectedPermission)
+	if err != nil {
+		return err
+	}
+
+	var roleID string
+	var isActive bool
+	err = tx.QueryRow(`SELECT role_id, is_active FROM users WHERE id = $1 FOR UPDATE`, id).Scan(&roleID, &isActive)
+	if err != nil {
+		return err
+	}
+
+	if isActive && containsString(holders, roleID) {
+		return ensureOtherPermissionHolder(tx, id, holders)
+	}
+	return nil
+}
+
+// ensureOtherPermissionHolder mengembalikan ErrLastPermissionHolder jika tidak ada user aktif selain
+// id yang memiliki salah satu role di holders.
+func ensureOtherPermissionHolder(tx *sql.Tx, id string, holders []string) error {
+	var otherHolders int
+	err := tx.QueryRow(
+		`SELECT COUNT(*) FROM users WHERE id <> $1 AND is_active = true AND role_id = ANY($2)`,
+		id, pq.Array(holders),
+	).Scan(&otherHolders)
+	if err != nil {
+		return err
+	}
+	if otherHolders == 0 {
+		return ErrLastPermissionHolder
+	}
+	return nil
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateUserActiveStatus mengubah status aktif user. Menonaktifkan user aktif terakhir yang memiliki
+// protectedPermission ditolak dengan ErrLastPermissionHolder.
+func UpdateUserActiveStatus(db *sql.DB, id string, isActive bool, protectedPermission string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if !isActive {
+		if err := lockUserForRemoval(tx, id, protectedPermission); err != nil {
+			return err
+		}
+	}
+
+	query := `UPDATE users SET is_active = $1, updated_at = NOW() WHERE id = $2`
+	result, err := tx.Exec(query, isActive, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+// DeleteUser menghapus akun yang tidak memiliki profil mahasiswa atau dosen. Penghapusan profil
+// akan ikut menghapus prestasi, riwayat, dan transkrip melalui cascade, sehingga user dengan profil
+// ditolak dengan ErrUserHasProfile dan sebaiknya dinonaktifkan. User aktif terakhir yang memiliki
+// protectedPermission ditolak dengan ErrLastPermissionHolder.
+func DeleteUser(db *sql.DB, id string, protectedPermission string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockUserForRemoval(tx, id, protectedPermission); err != nil {
+		return err
+	}
+
+	var hasProfile bool
+	err = tx.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM students WHERE user_id = $1)
+		    OR EXISTS (SELECT 1 FROM lecturers WHERE user_id = $1)
+	`, id).Scan(&hasProfile)
+	if err != nil {
+		return err
+	}
+	if hasProfile {
+		return ErrUserHasProfile
+	}
+
+	if _, err := tx.Exec(`DELETE FROM users WHERE id = $1`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 type RefreshToken struct {
 	ID        string
 	UserID    string
@@ -155,7 +420,7 @@ func GetRoleName(db *sql.DB, roleID string) (string, error) {
 
 func GetLecturerByUserID(db *sql.DB, userID string) (*model.Lecturer, error) {
 	query := `
-		SELECT l.id, l.user_id, l.lecturer_id, l.department, l.created_at
+		SELECT l.id, l.user_id, l.lecturer_id, COALESCE(l.department, ''), l.created_at
 		FROM lecturers l
 		WHERE l.user_id = $1
 	`
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"database/sql"
+	"path"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/helper"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Thread komentar memakai scope yang sama dengan detail prestasi: mahasiswa pemilik, dosen wali
+// mahasiswa tersebut, dan admin. Hanya penulis yang dapat mengedit komentarnya; penulis dan
+// user dengan scope baca semua prestasi (admin) dapat menghapusnya.
+
+func GetAchievementCommentsService(c *fiber.Ctx, postgresDB *sql.DB) error {
+	ref, ok, err := getReadableAchievementReference(c, postgresDB)
+	if !ok {
+		return err
+	}
+
+	comments, err := repositorypostgre.GetAchievementComments(postgresDB, ref.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil komentar prestasi. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := modelpostgre.GetAchievementCommentsResponse{
+		Status: "success",
+		Data:   comments,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func CreateAchievementCommentService(c *fiber.Ctx, postgresDB *sql.DB) error {
+	ref, policy, ok, err := getReadableAchievementReferenceWithPolicy(c, postgresDB)
+	if !ok {
+		return err
+	}
+
+	req, ok, err := parseAchievementCommentRequest(c)
+	if !ok {
+		return err
+	}
+
+	comment, err := repositorypostgre.CreateAchievementComment(postgresDB, ref.ID, policy.UserID, req)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error menyimpan komentar prestasi. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := modelpostgre.AchievementCommentResponse{
+		Status: "success",
+		Data:   *comment,
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+func UpdateAchievementCommentService(c *fiber.Ctx, postgresDB *sql.DB) error {
+	ref, policy, ok, err := getReadableAchievementReferenceWithPolicy(c, postgresDB)
+	if !ok {
+		return err
+	}
+
+	existing, ok, err := getAchievementComment(c, postgresDB, ref.ID)
+	if !ok {
+		return err
+	}
+
+	if existing.AuthorID == nil || *existing.AuthorID != policy.UserID {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Akses ditolak. Anda hanya dapat mengedit komentar milik Anda sendiri.",
+			},
+		})
+	}
+
+	req, ok, err := parseAchievementCommentRequest(c)
+	if !ok {
+		return err
+	}
+
+	comment, err := repositorypostgre.UpdateAchievementComment(postgresDB, ref.ID, existing.ID, req)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Komentar tidak ditemukan.",
+				},
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengupdate komentar prestasi. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := modelpostgre.AchievementCommentResponse{
+		Status: "success",
+		Data:   *comment,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func DeleteAchievementCommentService(c *fiber.Ctx, postgresDB *sql.DB) error {
+	ref, policy, ok, err := getReadableAchievementReferenceWithPolicy(c, postgresDB)
+	if !ok {
+		return err
+	}
+
+	existing, ok, err := getAchievementComment(c, postgresDB, ref.ID)
+	if !ok {
+		return err
+	}
+
+	isAuthor := existing.AuthorID != nil && *existing.AuthorID == policy.UserID
+	if !isAuthor && policy.Scope != modelpostgre.AchievementScopeAll {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Akses ditolak. Anda hanya dapat menghapus komentar milik Anda sendiri.",
+			},
+		})
+	}
+
+	if err := repositorypostgre.DeleteAchievementComment(postgresDB, ref.ID, existing.ID); err != nil && err != sql.ErrNoRows {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error menghapus komentar prestasi. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+	})
+}
+
+// getAchievementComment mengambil komentar dari parameter :commentId pada reference prestasi.
+// Jika ok bernilai false, response error sudah ditulis.
+func getAchievementComment(c *fiber.Ctx, postgresDB *sql.DB, referenceID string) (*modelpostgre.AchievementComment, bool, error) {
+	commentID := c.Params("commentId")
+	if !helper.IsValidUUID(commentID) {
+		return nil, false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "ID komentar tidak valid.",
+			},
+		})
+	}
+
+	comment, err := repositorypostgre.GetAchievementCommentByID(postgresDB, referenceID, commentID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Komentar tidak ditemukan.",
+				},
+			})
+		}
+		return nil, false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil komentar prestasi. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	return comment, true, nil
+}
+
+// parseAchievementCommentRequest membaca dan memvalidasi body komentar. Lampiran harus berupa
+// file yang sudah diunggah melalui /achievements/upload. Jika ok bernilai false, response error
+// sudah ditulis.
+func parseAchievementCommentRequest(c *fiber.Ctx) (modelpostgre.CreateAchievementCommentRequest, bool, error) {
+	var req modelpostgre.CreateAchievementCommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return req, false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Format request body tidak valid. Pastikan JSON format benar. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	req.Body = strings.TrimSpace(req.Body)
+	fieldErrors := []modelmongo.FieldError{}
+	if req.Body == "" {
+		fieldErrors = append(fieldErrors, modelmongo.FieldError{Field: "body", Message: "Isi komentar wajib diisi."})
+	} else if utf8.RuneCountInString(req.Body) > modelpostgre.MaxAchievementCommentLength {
+		fieldErrors = append(fieldErrors, modelmongo.FieldError{Field: "body", Message: "Isi komentar maksimal " + strconv.Itoa(modelpostgre.MaxAchievementCommentLength) + " karakter."})
+	}
+
+	if req.AttachmentURL != nil && strings.TrimSpace(*req.AttachmentURL) == "" {
+		req.AttachmentURL = nil
+	}
+	if req.AttachmentURL == nil {
+		req.AttachmentName = nil
+	} else {
+		attachmentURL := strings.TrimSpace(*req.AttachmentURL)
+		req.AttachmentURL = &attachmentURL
+		if !strings.HasPrefix(attachmentURL, "/uploads/") || strings.Contains(attachmentURL, "..") || len(attachmentURL) > 500 {
+			fieldErrors = append(fieldErrors, modelmongo.FieldError{Field: "attachment_url", Message: "Lampiran harus berupa URL file dari endpoint upload (/uploads/...)."})
+		}
+
+		if req.AttachmentName == nil || strings.TrimSpace(*req.AttachmentName) == "" {
+			attachmentName := path.Base(attachmentURL)
+			req.AttachmentName = &attachmentName
+		}
+		if len(*req.AttachmentName) > 255 {
+			fieldErrors = append(fieldErrors, modelmongo.FieldError{Field: "attachment_name", Message: "Nama lampiran maksimal 255 karakter."})
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		return req, false, helper.FieldErrorsResponse(c, "Validasi komentar gagal.", fieldErrors)
+	}
+
+	return req, true, nil
+}
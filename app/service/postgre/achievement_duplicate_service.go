@@ -0,0 +1,443 @@
+package service
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/helper"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// duplicateCandidateLimit membatasi kandidat per jenis pencarian saat create/submit.
+	duplicateCandidateLimit = 20
+	// duplicateReportBlockLimit melewati kata judul yang terlalu umum saat menyusun laporan
+	// agar jumlah pasangan yang dibandingkan tetap kecil.
+	duplicateReportBlockLimit = 200
+)
+
+// findAchievementDuplicates mencari prestasi aktif lain (milik siapa pun) yang mirip dengan
+// achievement, diurutkan dari skor tertinggi.
+func findAchievementDuplicates(mongoDB *mongo.Database, achievement modelmongo.Achievement) ([]modelmongo.DuplicateMatch, error) {
+	filter := modelmongo.DuplicateCandidateFilter{
+		ExcludeID:           achievement.ID,
+		AchievementType:     achievement.AchievementType,
+		Title:               achievement.Title,
+		CertificationNumber: achievement.Details.CertificationNumber,
+		CompetitionName:     achievement.Details.CompetitionName,
+		ISSN:                achievement.Details.ISSN,
+	}
+	for _, attachment := range achievement.Attachments {
+		if attachment.Checksum != "" {
+			filter.Checksums = append(filter.Checksums, attachment.Checksum)
+		}
+	}
+
+	candidates, err := repositorymongo.FindDuplicateCandidates(mongoDB, filter, duplicateCandidateLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []modelmongo.DuplicateMatch{}
+	for _, candidate := range candidates {
+		score, reasons := modelmongo.CompareAchievements(achievement, candidate)
+		if score >= modelmongo.DuplicateScoreThreshold {
+			matches = append(matches, modelmongo.DuplicateMatchFromAchievement(candidate, score, reasons))
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+
+	return matches, nil
+}
+
+// flagAchievementDuplicates menjalankan pemeriksaan duplikat dan mencatat setiap kecocokan sebagai
+// tanda untuk dosen wali, baik pada prestasi ini maupun pada prestasi yang cocok, sehingga pasangan
+// duplikat terlihat dari kedua sisi. Mengembalikan kecocokan dan tanda prestasi ini yang masih terbuka.
+func flagAchievementDuplicates(postgresDB *sql.DB, mongoDB *mongo.Database, referenceID string, achievement modelmongo.Achievement) ([]modelmongo.DuplicateMatch, []modelpostgre.AchievementDuplicateFlag, error) {
+	matches, err := findAchievementDuplicates(mongoDB, achievement)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	flags := []modelpostgre.AchievementDuplicateFlag{}
+	for _, match := range matches {
+		flag, err := repositorypostgre.UpsertAchievementDuplicateFlag(postgresDB, referenceID, match.AchievementID, match.Score, match.Reasons)
+		if err != nil {
+			return nil, nil, err
+		}
+		if flag.DismissedAt == nil {
+			flags = append(flags, *flag)
+		}
+
+		matchedRef, err := repositorypostgre.GetAchievementReferenceByMongoID(postgresDB, match.AchievementID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				continue
+			}
+			return nil, nil, err
+		}
+		if _, err := repositorypostgre.UpsertAchievementDuplicateFlag(postgresDB, matchedRef.ID, achievement.ID.Hex(), match.Score, match.Reasons); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return matches, flags, nil
+}
+
+// summarizeDuplicateFlags meringkas tanda duplikat yang masih terbuka menjadi jumlah dan alasan
+// kemiripan. Ringkasan ini yang dikirim ke mahasiswa; detail prestasi yang cocok (milik mahasiswa
+// lain) hanya tersedia untuk verifikator melalui daftar tanda duplikat.
+func summarizeDuplicateFlags(flags []modelpostgre.AchievementDuplicateFlag) (int, []string) {
+	seen := make(map[string]bool)
+	reasons := []string{}
+	for _, flag := range flags {
+		for _, reason := range flag.Reasons {
+			if !seen[reason] {
+				seen[reason] = true
+				reasons = append(reasons, reason)
+			}
+		}
+	}
+	sort.Strings(reasons)
+
+	return len(flags), reasons
+}
+
+// fillAttachmentChecksums menghitung checksum lampiran yang tersimpan di folder uploads. Nilai
+// checksum dari client tidak dipercaya; lampiran di luar uploads tidak memiliki checksum.
+func fillAttachmentChecksums(attachments []modelmongo.Attachment) {
+	for i := range attachments {
+		attachments[i].Checksum = ""
+		if !strings.HasPrefix(attachments[i].FileURL, "/uploads/") {
+			continue
+		}
+		checksum, err := fileChecksum(filepath.Join("./uploads", filepath.Base(attachments[i].FileURL)))
+		if err == nil {
+			attachments[i].Checksum = checksum
+		}
+	}
+}
+
+func fileChecksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// GetAchievementDuplicateFlagsService menampilkan tanda duplikat beserta prestasi yang cocok kepada
+// user yang berwenang memverifikasi prestasi tersebut.
+func GetAchievementDuplicateFlagsService(c *fiber.Ctx, postgresDB *sql.DB) error {
+	ref, _, ok, err := getReviewableAchievementReference(c, postgresDB)
+	if !ok {
+		return err
+	}
+
+	flags, err := repositorypostgre.GetAchievementDuplicateFlags(postgresDB, ref.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil tanda duplikat prestasi. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := modelpostgre.GetAchievementDuplicateFlagsResponse{
+		Status: "success",
+		Data:   flags,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// DismissAchievementDuplicateFlagService menutup tanda duplikat setelah ditinjau oleh user yang
+// berwenang memverifikasi prestasi tersebut.
+func DismissAchievementDuplicateFlagService(c *fiber.Ctx, postgresDB *sql.DB) error {
+	ref, userID, ok, err := getReviewableAchievementReference(c, postgresDB)
+	if !ok {
+		return err
+	}
+
+	flagID := c.Params("flagId")
+	if !helper.IsValidUUID(flagID) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "ID tanda duplikat tidak valid.",
+			},
+		})
+	}
+
+	if err := repositorypostgre.DismissAchievementDuplicateFlag(postgresDB, ref.ID, flagID, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Tanda duplikat tidak ditemukan atau sudah ditutup.",
+				},
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error menutup tanda duplikat. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	return GetAchievementDuplicateFlagsService(c, postgresDB)
+}
+
+// getReviewableAchievementReference mengambil reference prestasi yang tanda duplikatnya boleh
+// ditinjau user, yaitu prestasi dalam scope verifikasinya. Jika ok bernilai false, response error
+// sudah ditulis.
+func getReviewableAchievementReference(c *fiber.Ctx, postgresDB *sql.DB) (*modelpostgre.AchievementReference, string, bool, error) {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return nil, "", false, c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			},
+		})
+	}
+
+	policy, ok, err := resolveAchievementPolicy(c, postgresDB, userID, modelpostgre.AchievementActionVerify)
+	if !ok {
+		return nil, "", false, err
+	}
+
+	ref, err := repositorypostgre.GetAchievementReferenceByMongoID(postgresDB, c.Params("id"))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, "", false, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Prestasi tidak ditemukan.",
+				},
+			})
+		}
+		return nil, "", false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil data prestasi dari database. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	if ok, err := authorizeAchievementStudent(c, postgresDB, policy, ref.StudentID, "Akses ditolak. Anda hanya dapat meninjau prestasi mahasiswa bimbingan Anda."); !ok {
+		return nil, "", false, err
+	}
+
+	return ref, userID, true, nil
+}
+
+// GetDuplicateReportService menyusun laporan kelompok prestasi yang diduga duplikat di seluruh
+// koleksi achievements. Hanya untuk user dengan scope verifikasi semua prestasi (admin).
+func GetDuplicateReportService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.Database) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			},
+		})
+	}
+
+	policy, ok, err := resolveAchievementPolicy(c, postgresDB, userID, modelpostgre.AchievementActionVerify)
+	if !ok {
+		return err
+	}
+	if policy.Scope != modelpostgre.AchievementScopeAll {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Akses ditolak. Laporan duplikat membutuhkan scope '" + modelpostgre.AchievementScopePermission(modelpostgre.AchievementActionVerify, modelpostgre.AchievementScopeAll) + "'.",
+			},
+		})
+	}
+
+	achievements, err := repositorymongo.GetAchievementsForDuplicateScan(mongoDB)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil achievement dari database. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := modelmongo.GetDuplicateReportResponse{
+		Status: "success",
+		Data:   buildDuplicateReport(achievements),
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// buildDuplicateReport mengelompokkan prestasi ke blok dengan nilai yang sama (nomor sertifikat,
+// checksum, kompetisi dan tanggal, ISSN, atau kata judul), membandingkan pasangan di dalam blok,
+// lalu menggabungkan pasangan duplikat menjadi cluster.
+func buildDuplicateReport(achievements []modelmongo.Achievement) modelmongo.DuplicateReport {
+	blocks := make(map[string][]int)
+	addToBlock := func(key string, index int) {
+		blocks[key] = append(blocks[key], index)
+	}
+
+	for i, achievement := range achievements {
+		prefix := achievement.AchievementType + "|"
+		details := achievement.Details
+		if details.CertificationNumber != nil {
+			if value := modelmongo.NormalizeDuplicateText(*details.CertificationNumber); value != "" {
+				addToBlock("certification|"+prefix+value, i)
+			}
+		}
+		if details.ISSN != nil {
+			if value := modelmongo.NormalizeDuplicateText(*details.ISSN); value != "" {
+				addToBlock("issn|"+prefix+value, i)
+			}
+		}
+		if details.CompetitionName != nil && details.EventDate != nil {
+			if value := modelmongo.NormalizeDuplicateText(*details.CompetitionName); value != "" {
+				addToBlock("competition|"+prefix+value+"|"+details.EventDate.UTC().Format("2006-01-02"), i)
+			}
+		}
+		for _, attachment := range achievement.Attachments {
+			if attachment.Checksum != "" {
+				addToBlock("checksum|"+prefix+attachment.Checksum, i)
+			}
+		}
+		for _, token := range modelmongo.DuplicateTitleTokens(achievement.Title) {
+			addToBlock("title|"+prefix+token, i)
+		}
+	}
+
+	parent := make([]int, len(achievements))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	type pairResult struct {
+		score   float64
+		reasons []string
+	}
+	compared := make(map[[2]int]bool)
+	pairs := []struct {
+		a, b   int
+		result pairResult
+	}{}
+
+	for _, members := range blocks {
+		if len(members) < 2 || len(members) > duplicateReportBlockLimit {
+			continue
+		}
+		for x := 0; x < len(members); x++ {
+			for y := x + 1; y < len(members); y++ {
+				a, b := members[x], members[y]
+				if a > b {
+					a, b = b, a
+				}
+				key := [2]int{a, b}
+				if a == b || compared[key] {
+					continue
+				}
+				compared[key] = true
+
+				score, reasons := modelmongo.CompareAchievements(achievements[a], achievements[b])
+				if score < modelmongo.DuplicateScoreThreshold {
+					continue
+				}
+				pairs = append(pairs, struct {
+					a, b   int
+					result pairResult
+				}{a, b, pairResult{score, reasons}})
+				parent[find(a)] = find(b)
+			}
+		}
+	}
+
+	type clusterState struct {
+		cluster modelmongo.DuplicateCluster
+		members map[int]bool
+		reasons map[string]bool
+	}
+	clusters := make(map[int]*clusterState)
+	for _, pair := range pairs {
+		root := find(pair.a)
+		state, exists := clusters[root]
+		if !exists {
+			state = &clusterState{members: make(map[int]bool), reasons: make(map[string]bool)}
+			clusters[root] = state
+		}
+		state.members[pair.a] = true
+		state.members[pair.b] = true
+		for _, reason := range pair.result.reasons {
+			state.reasons[reason] = true
+		}
+		if pair.result.score > state.cluster.Score {
+			state.cluster.Score = pair.result.score
+		}
+	}
+
+	report := modelmongo.DuplicateReport{
+		Scanned:  len(achievements),
+		Clusters: []modelmongo.DuplicateCluster{},
+	}
+	for _, state := range clusters {
+		indexes := make([]int, 0, len(state.members))
+		for index := range state.members {
+			indexes = append(indexes, index)
+		}
+		sort.Slice(indexes, func(i, j int) bool {
+			return achievements[indexes[i]].CreatedAt.Before(achievements[indexes[j]].CreatedAt)
+		})
+		for _, index := range indexes {
+			state.cluster.Achievements = append(state.cluster.Achievements, modelmongo.DuplicateClusterItemFromAchievement(achievements[index]))
+		}
+
+		for reason := range state.reasons {
+			state.cluster.Reasons = append(state.cluster.Reasons, reason)
+		}
+		sort.Strings(state.cluster.Reasons)
+		report.Clusters = append(report.Clusters, state.cluster)
+	}
+
+	sort.Slice(report.Clusters, func(i, j int) bool {
+		if report.Clusters[i].Score != report.Clusters[j].Score {
+			return report.Clusters[i].Score > report.Clusters[j].Score
+		}
+		return len(report.Clusters[i].Achievements) > len(report.Clusters[j].Achievements)
+	})
+
+	return report
+}
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xuri/excelize/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// achievementExportTimeout membatasi lama satu export, termasuk pengiriman ke client.
+	achievementExportTimeout = 10 * time.Minute
+	// achievementExportFlushRows menentukan seberapa sering baris CSV/JSON dikirim ke client.
+	achievementExportFlushRows = 100
+	// achievementExportBatchSize adalah jumlah reference yang dibaca dari PostgreSQL, dan dokumen
+	// yang diambil dari MongoDB, per batch.
+	achievementExportBatchSize = 500
+)
+
+var achievementExportContentTypes = map[string]string{
+	modelmongo.AchievementExportFormatCSV:  "text/csv; charset=utf-8",
+	modelmongo.AchievementExportFormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	modelmongo.AchievementExportFormatJSON: fiber.MIMEApplicationJSONCharsetUTF8,
+}
+
+// ExportAchievementsService mengunduh prestasi sebagai CSV, XLSX, atau JSON (query format,
+// default csv). Scope dan filter sama dengan GET /achievements, tanpa pagination, urut dari prestasi
+// terbaru. Reference dibaca per batch dan setiap batch ditulis ke response sebelum batch berikutnya
+// diambil, sehingga export besar tidak dimuat sekaligus.
+func ExportAchievementsService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.Database) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			},
+		})
+	}
+
+	format := c.Query("format", modelmongo.AchievementExportFormatCSV)
+	if !modelmongo.IsValidAchievementExportFormat(format) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Format export tidak valid. Gunakan csv, xlsx, atau json.",
+			},
+		})
+	}
+
+	policy, ok, err := resolveAchievementPolicy(c, postgresDB, userID, modelpostgre.AchievementActionRead)
+	if !ok {
+		return err
+	}
+
+	referenceFilter, achievementFilter, ok, err := parseAchievementListQuery(c)
+	if !ok {
+		return err
+	}
+	scopeAchievementReferenceFilter(policy, &referenceFilter)
+
+	// Batch pertama diambil sebelum response dimulai agar error database masih dapat dikirim sebagai JSON.
+	firstBatch, err := repositorypostgre.GetAchievementExportReferences(postgresDB, referenceFilter, nil, achievementExportBatchSize)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil achievement references. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	filename := fmt.Sprintf("achievements-%s.%s", time.Now().Format("20060102-150405"), format)
+	c.Set(fiber.HeaderContentType, achievementExportContentTypes[format])
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	// Body ditulis setelah handler selesai, sehingga error di tengah export hanya dapat dicatat;
+	// response yang terpotong menandakan export gagal.
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithTimeout(context.Background(), achievementExportTimeout)
+		defer cancel()
+
+		forEach := func(fn func(modelmongo.AchievementExportRow) error) error {
+			references := firstBatch
+			for len(references) > 0 {
+				if err := exportAchievementBatch(ctx, mongoDB, achievementFilter, references, fn); err != nil {
+					return err
+				}
+				if len(references) < achievementExportBatchSize {
+					return nil
+				}
+
+				var err error
+				references, err = repositorypostgre.GetAchievementExportReferences(postgresDB, referenceFilter, &references[len(references)-1], achievementExportBatchSize)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		var err error
+		switch format {
+		case modelmongo.AchievementExportFormatXLSX:
+			err = writeAchievementExportXLSX(w, forEach)
+		case modelmongo.AchievementExportFormatJSON:
+			err = writeAchievementExportJSON(w, forEach)
+		default:
+			err = writeAchievementExportCSV(w, forEach)
+		}
+		if err == nil {
+			err = w.Flush()
+		}
+		if err != nil {
+			log.Printf("Achievement export (%s) for user %s failed: %v", format, userID, err)
+		}
+	})
+
+	return nil
+}
+
+// exportAchievementBatch mengambil dokumen MongoDB untuk satu batch reference yang juga cocok dengan
+// filter MongoDB, lalu memanggil fn per baris sesuai urutan reference.
+func exportAchievementBatch(ctx context.Context, mongoDB *mongo.Database, achievementFilter modelmongo.AchievementFilter, references []modelpostgre.AchievementExportReference, fn func(modelmongo.AchievementExportRow) error) error {
+	achievementFilter.IDs = make([]string, 0, len(references))
+	for _, ref := range references {
+		achievementFilter.IDs = append(achievementFilter.IDs, ref.MongoAchievementID)
+	}
+
+	achievements := make(map[string]modelmongo.Achievement, len(references))
+	err := repositorymongo.ForEachAchievementByFilter(ctx, mongoDB, achievementFilter, func(achievement modelmongo.Achievement) error {
+		achievements[achievement.ID.Hex()] = achievement
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, ref := range references {
+		achievement, exists := achievements[ref.MongoAchievementID]
+		if !exists {
+			continue
+		}
+		if err := fn(newAchievementExportRow(achievement, ref)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newAchievementExportRow(achievement modelmongo.Achievement, ref modelpostgre.AchievementExportReference) modelmongo.AchievementExportRow {
+	return modelmongo.AchievementExportRow{
+		AchievementID:   achievement.ID.Hex(),
+		StudentNumber:   ref.StudentNumber,
+		StudentName:     ref.StudentName,
+		ProgramStudy:    ref.ProgramStudy,
+		AchievementType: achievement.AchievementType,
+		Title:           achievement.Title,
+		Description:     achievement.Description,
+		Status:          ref.Status,
+		Points:          achievement.Points,
+		Tags:            achievement.Tags,
+		Details:         achievement.Details,
+		Attachments:     achievement.Attachments,
+		SubmittedAt:     ref.SubmittedAt,
+		VerifiedAt:      ref.VerifiedAt,
+		VerifiedBy:      ref.VerifierName,
+		RejectionNote:   ref.RejectionNote,
+		CreatedAt:       achievement.CreatedAt,
+		UpdatedAt:       achievement.UpdatedAt,
+	}
+}
+
+type achievementExportIterator func(fn func(modelmongo.AchievementExportRow) error) error
+
+func writeAchievementExportCSV(w *bufio.Writer, forEach achievementExportIterator) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(modelmongo.AchievementExportColumns); err != nil {
+		return err
+	}
+
+	count := 0
+	err := forEach(func(row modelmongo.AchievementExportRow) error {
+		if err := writer.Write(row.Values()); err != nil {
+			return err
+		}
+		count++
+		if count%achievementExportFlushRows == 0 {
+			writer.Flush()
+			if err := writer.Error(); err != nil {
+				return err
+			}
+			return w.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeAchievementExportJSON menulis response dengan bentuk yang sama seperti endpoint lain,
+// {"status":"success","data":[...]}, satu elemen data per prestasi.
+func writeAchievementExportJSON(w *bufio.Writer, forEach achievementExportIterator) error {
+	if _, err := io.WriteString(w, `{"status":"success","data":[`); err != nil {
+		return err
+	}
+
+	count := 0
+	err := forEach(func(row modelmongo.AchievementExportRow) error {
+		if count > 0 {
+			if err := w.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		data, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+		count++
+		if count%achievementExportFlushRows == 0 {
+			return w.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "]}")
+	return err
+}
+
+// writeAchievementExportXLSX memakai StreamWriter excelize yang menyimpan baris ke file sementara
+// saat datanya besar. File XLSX berupa arsip zip sehingga baru dapat dikirim setelah baris terakhir.
+func writeAchievementExportXLSX(w *bufio.Writer, forEach achievementExportIterator) error {
+	file := excelize.NewFile()
+	defer file.Close()
+
+	sheet := file.GetSheetName(0)
+	stream, err := file.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+
+	header := make([]interface{}, len(modelmongo.AchievementExportColumns))
+	for i, column := range modelmongo.AchievementExportColumns {
+		header[i] = column
+	}
+	if err := stream.SetRow("A1", header); err != nil {
+		return err
+	}
+
+	rowNumber := 1
+	err = forEach(func(row modelmongo.AchievementExportRow) error {
+		rowNumber++
+		cell, err := excelize.CoordinatesToCellName(1, rowNumber)
+		if err != nil {
+			return err
+		}
+		return stream.SetRow(cell, row.Cells())
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := stream.Flush(); err != nil {
+		return err
+	}
+	return file.Write(w)
+}
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/helper"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// maxAchievementImportFileSize membatasi ukuran file import yang diunggah.
+const maxAchievementImportFileSize = 10 * 1024 * 1024
+
+// ImportAchievementsService mengimport prestasi dari file CSV/XLSX (field multipart "file").
+// Form dry_run=true hanya memvalidasi, sedangkan initial_status=verified membuat prestasi yang
+// langsung terverifikasi. Hanya untuk user dengan scope write semua prestasi (admin); status
+// verified juga membutuhkan scope verify semua prestasi.
+func ImportAchievementsService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.Database) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			},
+		})
+	}
+
+	opts := modelmongo.AchievementImportOptions{
+		InitialStatus: strings.ToLower(strings.TrimSpace(c.FormValue("initial_status", modelpostgre.AchievementStatusDraft))),
+		ImportedBy:    userID,
+	}
+	if dryRun := c.FormValue("dry_run"); dryRun != "" {
+		value, err := strconv.ParseBool(dryRun)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Nilai dry_run tidak valid. Gunakan true atau false.",
+				},
+			})
+		}
+		opts.DryRun = value
+	}
+	if !isValidAchievementImportStatus(opts.InitialStatus) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Initial status tidak valid. Gunakan: draft atau verified.",
+			},
+		})
+	}
+
+	actions := []string{modelpostgre.AchievementActionWrite}
+	if opts.InitialStatus == modelpostgre.AchievementStatusVerified {
+		actions = append(actions, modelpostgre.AchievementActionVerify)
+	}
+	for _, action := range actions {
+		policy, ok, err := resolveAchievementPolicy(c, postgresDB, userID, action)
+		if !ok {
+			return err
+		}
+		if policy.Scope != modelpostgre.AchievementScopeAll {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Akses ditolak. Import prestasi membutuhkan scope '" + modelpostgre.AchievementScopePermission(action, modelpostgre.AchievementScopeAll) + "'.",
+				},
+			})
+		}
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "File tidak ditemukan. Pastikan field name adalah 'file'.",
+			},
+		})
+	}
+	if file.Size > maxAchievementImportFileSize {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Ukuran file terlalu besar. Maksimal 10MB.",
+			},
+		})
+	}
+
+	content, err := file.Open()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error membuka file. Detail: " + err.Error(),
+			},
+		})
+	}
+	defer content.Close()
+
+	records, err := helper.ReadSpreadsheet(file.Filename, content)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "File import tidak dapat dibaca. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	rows, err := modelmongo.ParseAchievementImportRecords(records)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Format file import tidak valid. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	report, err := ImportAchievements(postgresDB, mongoDB, rows, opts)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengimport prestasi. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := modelmongo.ImportAchievementsResponse{
+		Status: "success",
+		Data:   *report,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// CheckAchievementImportPermission memastikan user memiliki scope yang dibutuhkan untuk import
+// dengan initialStatus. Dipakai oleh perintah cmd/import.
+func CheckAchievementImportPermission(postgresDB *sql.DB, userID string, initialStatus string) error {
+	permissions, err := repositorypostgre.GetUserPermissions(postgresDB, userID)
+	if err != nil {
+		return err
+	}
+
+	granted := make(map[string]bool)
+	for _, permission := range permissions {
+		granted[permission] = true
+	}
+
+	required := []string{modelpostgre.AchievementScopePermission(modelpostgre.AchievementActionWrite, modelpostgre.AchievementScopeAll)}
+	if initialStatus == modelpostgre.AchievementStatusVerified {
+		required = append(required, modelpostgre.AchievementScopePermission(modelpostgre.AchievementActionVerify, modelpostgre.AchievementScopeAll))
+	}
+	for _, permission := range required {
+		if !granted[permission] {
+			return fmt.Errorf("user tidak memiliki permission %s", permission)
+		}
+	}
+	return nil
+}
+
+// ImportAchievements memvalidasi setiap baris lalu, jika bukan dry-run, membuat dokumen MongoDB
+// dan reference PostgreSQL per baris dengan alur outbox yang sama seperti create prestasi.
+// Kegagalan satu baris dicatat di laporan tanpa menghentikan baris lainnya; error hanya
+// dikembalikan untuk kegagalan yang memengaruhi seluruh import.
+func ImportAchievements(postgresDB *sql.DB, mongoDB *mongo.Database, rows []modelmongo.AchievementImportRow, opts modelmongo.AchievementImportOptions) (*modelmongo.AchievementImportReport, error) {
+	if opts.InitialStatus == "" {
+		opts.InitialStatus = modelpostgre.AchievementStatusDraft
+	}
+	if !isValidAchievementImportStatus(opts.InitialStatus) {
+		return nil, fmt.Errorf("initial status tidak valid: %s", opts.InitialStatus)
+	}
+
+	studentNumbers := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if row.StudentNumber != "" {
+			studentNumbers = append(studentNumbers, row.StudentNumber)
+		}
+	}
+	studentIDs, err := repositorypostgre.GetStudentIDsByStudentNumbers(postgresDB, studentNumbers)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &modelmongo.AchievementImportReport{
+		DryRun:        opts.DryRun,
+		InitialStatus: opts.InitialStatus,
+		Total:         len(rows),
+		Rows:          make([]modelmongo.AchievementImportRowResult, 0, len(rows)),
+	}
+
+	// Baris valid sebelumnya dipakai untuk mendeteksi prestasi yang tercantum dua kali dalam file
+	// yang sama, karena baris tersebut belum ada di MongoDB saat dry-run.
+	var accepted []importedAchievement
+	for _, row := range rows {
+		result, achievement := prepareAchievementImportRow(postgresDB, row, studentIDs)
+		if achievement != nil {
+			if earlier, reasons, found := findDuplicateImportRow(accepted, *achievement); found {
+				result.Errors = append(result.Errors, modelmongo.FieldError{
+					Field:   "row",
+					Message: fmt.Sprintf("Duplikat dengan baris %d pada file yang sama (%s).", earlier, strings.Join(reasons, ", ")),
+				})
+			} else {
+				accepted = append(accepted, importedAchievement{Row: row.Row, Achievement: *achievement})
+				result = importAchievementRow(postgresDB, mongoDB, result, *achievement, opts)
+			}
+		}
+
+		switch result.Status {
+		case modelmongo.AchievementImportStatusValid:
+			report.Valid++
+		case modelmongo.AchievementImportStatusCreated:
+			report.Valid++
+			report.Created++
+		default:
+			report.Failed++
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	return report, nil
+}
+
+// importedAchievement adalah baris file import yang sudah lolos validasi.
+type importedAchievement struct {
+	Row         int
+	Achievement modelmongo.Achievement
+}
+
+// findDuplicateImportRow mencari baris sebelumnya milik mahasiswa yang sama yang dinilai duplikat
+// dengan achievement, lalu mengembalikan nomor baris dan alasan kemiripannya.
+func findDuplicateImportRow(accepted []importedAchievement, achievement modelmongo.Achievement) (int, []string, bool) {
+	for _, earlier := range accepted {
+		if earlier.Achievement.StudentID != achievement.StudentID {
+			continue
+		}
+		if score, reasons := modelmongo.CompareAchievements(earlier.Achievement, achievement); score >= modelmongo.DuplicateScoreThreshold {
+			return earlier.Row, reasons, true
+		}
+	}
+	return 0, nil, false
+}
+
+// prepareAchievementImportRow memvalidasi satu baris dan menyusun dokumen prestasinya. Dokumen
+// bernilai nil jika baris tidak valid; alasannya dicatat di Errors pada hasil baris.
+func prepareAchievementImportRow(postgresDB *sql.DB, row modelmongo.AchievementImportRow, studentIDs map[string]string) (modelmongo.AchievementImportRowResult, *modelmongo.Achievement) {
+	req := row.Request
+	result := modelmongo.AchievementImportRowResult{
+		Row:             row.Row,
+		StudentNumber:   row.StudentNumber,
+		AchievementType: req.AchievementType,
+		Title:           req.Title,
+		Status:          modelmongo.AchievementImportStatusFailed,
+		Errors:          append([]modelmongo.FieldError{}, row.Errors...),
+	}
+
+	if row.StudentNumber == "" {
+		result.Errors = append(result.Errors, modelmongo.FieldError{Field: "nim", Message: "NIM wajib diisi."})
+	} else if studentID, ok := studentIDs[row.StudentNumber]; ok {
+		req.StudentID = studentID
+	} else {
+		result.Errors = append(result.Errors, modelmongo.FieldError{Field: "nim", Message: "Mahasiswa dengan NIM " + row.StudentNumber + " tidak ditemukan."})
+	}
+	result.Errors = append(result.Errors, modelmongo.ValidateCreateAchievementRequest(req)...)
+	if len(result.Errors) > 0 {
+		return result, nil
+	}
+
+	points, pointRubricVersion, err := calculateAchievementPoints(postgresDB, req.AchievementType, req.Details)
+	if err != nil {
+		result.Errors = append(result.Errors, modelmongo.FieldError{Field: "points", Message: "Error menghitung poin prestasi. Detail: " + err.Error()})
+		return result, nil
+	}
+	result.Points = &points
+
+	now := time.Now()
+	return result, &modelmongo.Achievement{
+		ID:                 primitive.NewObjectID(),
+		StudentID:          req.StudentID,
+		AchievementType:    req.AchievementType,
+		Title:              req.Title,
+		Description:        req.Description,
+		Details:            req.Details,
+		Tags:               req.Tags,
+		Points:             points,
+		PointRubricVersion: pointRubricVersion,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+}
+
+// importAchievementRow menyimpan baris yang sudah valid dengan alur yang sama seperti create
+// prestasi. Pada dry-run, baris hanya diperiksa terhadap prestasi yang sudah ada.
+func importAchievementRow(postgresDB *sql.DB, mongoDB *mongo.Database, result modelmongo.AchievementImportRowResult, achievement modelmongo.Achievement, opts modelmongo.AchievementImportOptions) modelmongo.AchievementImportRowResult {
+	if opts.DryRun {
+		duplicates, err := findAchievementDuplicates(mongoDB, achievement)
+		if err != nil {
+			log.Printf("Failed to check duplicates for import row %d: %v", result.Row, err)
+		}
+		result.Duplicates = duplicates
+		result.Status = modelmongo.AchievementImportStatusValid
+		return result
+	}
+
+	creation, err := createAchievementWithOutbox(postgresDB, mongoDB, achievement, opts.InitialStatus, opts.ImportedBy, nil)
+	if err != nil {
+		result.Errors = append(result.Errors, modelmongo.FieldError{Field: "row", Message: err.Error()})
+		return result
+	}
+
+	result.Status = modelmongo.AchievementImportStatusCreated
+	result.AchievementID = creation.Achievement.ID.Hex()
+	result.Duplicates = creation.Duplicates
+	return result
+}
+
+func isValidAchievementImportStatus(status string) bool {
+	return status == modelpostgre.AchievementStatusDraft || status == modelpostgre.AchievementStatusVerified
+}
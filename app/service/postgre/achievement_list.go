@@ -0,0 +1,197 @@
+package service
+
+import (
+	"bytes"
+	"cmp"
+	"database/sql"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// achievementListBatchSize adalah jumlah reference yang dibaca dari PostgreSQL per batch saat daftar
+// prestasi difilter atau diurutkan dengan field MongoDB.
+const achievementListBatchSize = 500
+
+type achievementListItem struct {
+	Achievement modelmongo.Achievement
+	Reference   modelpostgre.AchievementReference
+	Score       float64
+}
+
+// listAchievements mengembalikan satu halaman prestasi beserta jumlah seluruh prestasi yang cocok.
+// Tanpa filter dan urutan MongoDB, halaman diambil langsung dari PostgreSQL sehingga hanya dokumen
+// di halaman tersebut yang dibaca dari MongoDB. Selain itu reference dibaca per batch.
+func listAchievements(postgresDB *sql.DB, mongoDB *mongo.Database, referenceFilter modelpostgre.AchievementReferenceFilter, achievementFilter modelmongo.AchievementFilter, offset int, limit int) ([]achievementListItem, int, error) {
+	if !hasAchievementDocumentQuery(achievementFilter) {
+		references, total, err := repositorypostgre.GetAchievementReferencePage(postgresDB, referenceFilter, offset, limit)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		mongoIDs := make([]string, 0, len(references))
+		for _, ref := range references {
+			mongoIDs = append(mongoIDs, ref.MongoAchievementID)
+		}
+		achievements, err := repositorymongo.GetAchievementsByIDs(mongoDB, mongoIDs)
+		if err != nil {
+			return nil, 0, err
+		}
+		achievementMap := make(map[string]modelmongo.Achievement, len(achievements))
+		for _, achievement := range achievements {
+			achievementMap[achievement.ID.Hex()] = achievement
+		}
+
+		items := make([]achievementListItem, 0, len(references))
+		for _, ref := range references {
+			if achievement, exists := achievementMap[ref.MongoAchievementID]; exists {
+				items = append(items, achievementListItem{Achievement: achievement, Reference: ref})
+			}
+		}
+		return items, total, nil
+	}
+
+	fetch := func(ids []string, limit int) ([]achievementListItem, int, error) {
+		filter := achievementFilter
+		filter.IDs = ids
+		filter.Offset = 0
+		filter.Limit = limit
+		achievements, count, err := repositorymongo.GetAchievementsByFilter(mongoDB, filter)
+		if err != nil {
+			return nil, 0, err
+		}
+		items := make([]achievementListItem, 0, len(achievements))
+		for _, achievement := range achievements {
+			items = append(items, achievementListItem{Achievement: achievement})
+		}
+		return items, count, nil
+	}
+
+	return collectAchievementPage(postgresDB, referenceFilter, offset, limit, fetch, achievementSortLess(achievementFilter))
+}
+
+// searchAchievements seperti listAchievements untuk text search. Skor relevansi hanya tersedia di
+// MongoDB, sehingga reference selalu dibaca per batch.
+func searchAchievements(postgresDB *sql.DB, mongoDB *mongo.Database, search string, referenceFilter modelpostgre.AchievementReferenceFilter, achievementFilter modelmongo.AchievementFilter, offset int, limit int) ([]achievementListItem, int, error) {
+	fetch := func(ids []string, limit int) ([]achievementListItem, int, error) {
+		filter := achievementFilter
+		filter.IDs = ids
+		filter.Offset = 0
+		filter.Limit = limit
+		results, count, err := repositorymongo.SearchAchievements(mongoDB, search, filter)
+		if err != nil {
+			return nil, 0, err
+		}
+		items := make([]achievementListItem, 0, len(results))
+		for _, result := range results {
+			items = append(items, achievementListItem{Achievement: result.Achievement, Score: result.Score})
+		}
+		return items, count, nil
+	}
+
+	// Urutan sama dengan SearchAchievements: skor tertinggi lebih dulu, lalu _id menurun.
+	less := func(a, b achievementListItem) bool {
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return bytes.Compare(a.Achievement.ID[:], b.Achievement.ID[:]) > 0
+	}
+
+	return collectAchievementPage(postgresDB, referenceFilter, offset, limit, fetch, less)
+}
+
+// collectAchievementPage membaca reference per batch dengan keyset pagination dan mengambil paling
+// banyak offset+limit dokumen teratas setiap batch melalui fetch. Hasil semua batch digabung dengan
+// urutan less, dan hanya offset+limit kandidat teratas yang disimpan, sehingga query $in ke MongoDB
+// dan memori yang dipakai tidak bergantung pada jumlah seluruh reference.
+func collectAchievementPage(postgresDB *sql.DB, referenceFilter modelpostgre.AchievementReferenceFilter, offset int, limit int, fetch func(ids []string, limit int) ([]achievementListItem, int, error), less func(a, b achievementListItem) bool) ([]achievementListItem, int, error) {
+	keep := offset + limit
+	candidates := []achievementListItem{}
+	total := 0
+
+	var after *modelpostgre.AchievementReference
+	for {
+		references, err := repositorypostgre.GetAchievementReferenceBatch(postgresDB, referenceFilter, after, achievementListBatchSize)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(references) == 0 {
+			break
+		}
+
+		ids := make([]string, 0, len(references))
+		referenceMap := make(map[string]modelpostgre.AchievementReference, len(references))
+		for _, ref := range references {
+			ids = append(ids, ref.MongoAchievementID)
+			referenceMap[ref.MongoAchievementID] = ref
+		}
+
+		items, count, err := fetch(ids, keep)
+		if err != nil {
+			return nil, 0, err
+		}
+		total += count
+		for _, item := range items {
+			item.Reference = referenceMap[item.Achievement.ID.Hex()]
+			candidates = append(candidates, item)
+		}
+
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return less(candidates[i], candidates[j])
+		})
+		if len(candidates) > keep {
+			candidates = candidates[:keep]
+		}
+
+		if len(references) < achievementListBatchSize {
+			break
+		}
+		after = &references[len(references)-1]
+	}
+
+	if offset >= len(candidates) {
+		return []achievementListItem{}, total, nil
+	}
+	return candidates[offset:], total, nil
+}
+
+// hasAchievementDocumentQuery menandakan filter atau urutan memakai field dokumen MongoDB, sehingga
+// halaman tidak dapat diambil langsung dari PostgreSQL.
+func hasAchievementDocumentQuery(filter modelmongo.AchievementFilter) bool {
+	defaultSort := filter.SortField == "" || (filter.SortField == "createdAt" && filter.SortOrder == -1)
+	return filter.AchievementType != "" || len(filter.Tags) > 0 || filter.MinPoints != nil || filter.MaxPoints != nil ||
+		filter.DateFrom != nil || filter.DateTo != nil || !defaultSort
+}
+
+// achievementSortLess mengembalikan urutan yang sama dengan GetAchievementsByFilter: field sort
+// lalu _id, keduanya dengan arah SortOrder (default createdAt menurun).
+func achievementSortLess(filter modelmongo.AchievementFilter) func(a, b achievementListItem) bool {
+	order := filter.SortOrder
+	if order == 0 {
+		order = -1
+	}
+
+	return func(a, b achievementListItem) bool {
+		x, y := a.Achievement, b.Achievement
+		var result int
+		switch filter.SortField {
+		case "updatedAt":
+			result = x.UpdatedAt.Compare(y.UpdatedAt)
+		case "points":
+			result = cmp.Compare(x.Points, y.Points)
+		case "title":
+			result = strings.Compare(x.Title, y.Title)
+		default:
+			result = x.CreatedAt.Compare(y.CreatedAt)
+		}
+		if result == 0 {
+			result = bytes.Compare(x.ID[:], y.ID[:])
+		}
+		return result*order < 0
+	}
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/helper"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// achievementOutboxGracePeriod memberi waktu request yang sedang berjalan untuk menutup entri
+	// outbox-nya sendiri sebelum worker ikut memproses.
+	achievementOutboxGracePeriod = time.Minute
+	achievementOutboxMaxAttempts = 10
+)
+
+// errAchievementCreationInProgress dikembalikan createAchievementWithOutbox ketika entri outbox dengan
+// Idempotency-Key yang sama sudah ada.
+var errAchievementCreationInProgress = errors.New("request dengan Idempotency-Key yang sama sedang diproses")
+
+// achievementCreation adalah hasil createAchievementWithOutbox. DuplicateFlags berisi tanda duplikat
+// prestasi baru yang masih terbuka.
+type achievementCreation struct {
+	Achievement    *modelmongo.Achievement
+	Reference      *modelpostgre.AchievementReference
+	Duplicates     []modelmongo.DuplicateMatch
+	DuplicateFlags []modelpostgre.AchievementDuplicateFlag
+}
+
+// createAchievementWithOutbox menyimpan dokumen MongoDB dan reference PostgreSQL berstatus status
+// lewat outbox: niat pembuatan dicatat lebih dulu, dokumen disimpan, lalu reference dibuat dan
+// entri outbox ditutup dalam satu transaksi. Jika langkah terakhir gagal, dokumen dihapus kembali.
+// Revisi awal dan pemeriksaan duplikat tidak wajib untuk konsistensi, sehingga kegagalannya hanya
+// dicatat di log.
+func createAchievementWithOutbox(postgresDB *sql.DB, mongoDB *mongo.Database, achievement modelmongo.Achievement, status string, createdBy string, idempotencyKey *string) (*achievementCreation, error) {
+	outbox, err := repositorypostgre.CreateAchievementOutbox(postgresDB, modelpostgre.AchievementOutboxOperationCreate, achievement.ID.Hex(), createdBy, idempotencyKey, achievementOutboxGracePeriod)
+	if err != nil {
+		if _, ok := helper.IsUniqueViolation(err); ok {
+			return nil, errAchievementCreationInProgress
+		}
+		return nil, fmt.Errorf("Error mencatat outbox prestasi. Detail: %w", err)
+	}
+
+	createdAchievement, err := repositorymongo.CreateAchievement(mongoDB, achievement)
+	if err != nil {
+		if markErr := repositorypostgre.MarkAchievementOutboxStatus(postgresDB, outbox.ID, modelpostgre.AchievementOutboxStatusCompensated); markErr != nil {
+			log.Printf("Failed to close achievement outbox %s: %v", outbox.ID, markErr)
+		}
+		return nil, fmt.Errorf("Error menyimpan prestasi ke database. Detail: %w", err)
+	}
+
+	refReq := modelpostgre.CreateAchievementReferenceRequest{
+		StudentID:          achievement.StudentID,
+		MongoAchievementID: createdAchievement.ID.Hex(),
+		Status:             status,
+	}
+	ref, err := repositorypostgre.CompleteAchievementCreation(postgresDB, outbox.ID, refReq, createdBy)
+	if err != nil {
+		// Kompensasi langsung; jika gagal, worker outbox akan mengulanginya.
+		if purgeErr := repositorymongo.PurgeAchievement(mongoDB, createdAchievement.ID.Hex()); purgeErr == nil {
+			repositorypostgre.MarkAchievementOutboxStatus(postgresDB, outbox.ID, modelpostgre.AchievementOutboxStatusCompensated)
+		}
+		return nil, fmt.Errorf("Error membuat reference prestasi. Detail: %w", err)
+	}
+
+	// Jika revisi awal gagal dibuat, revisi dibuat dari isi dokumen saat update pertama.
+	if _, err := repositorymongo.CreateAchievementRevision(mongoDB, modelmongo.AchievementRevision{
+		AchievementID: createdAchievement.ID,
+		EditedBy:      createdBy,
+		Snapshot:      modelmongo.NewAchievementSnapshot(*createdAchievement),
+	}); err != nil {
+		log.Printf("Failed to record initial revision for achievement %s: %v", createdAchievement.ID.Hex(), err)
+	}
+
+	creation := &achievementCreation{Achievement: createdAchievement, Reference: ref}
+	creation.Duplicates, creation.DuplicateFlags, err = flagAchievementDuplicates(postgresDB, mongoDB, ref.ID, *createdAchievement)
+	if err != nil {
+		log.Printf("Failed to check duplicates for achievement %s: %v", createdAchievement.ID.Hex(), err)
+	}
+
+	return creation, nil
+}
+
+// StartAchievementOutboxWorker memproses entri outbox yang jatuh tempo setiap interval.
+// Fungsi ini memblokir sehingga perlu dijalankan dalam goroutine.
+func StartAchievementOutboxWorker(postgresDB *sql.DB, mongoDB *mongo.Database, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		processed, err := ProcessAchievementOutbox(postgresDB, mongoDB, 100)
+		if err != nil {
+			log.Printf("Achievement outbox worker error: %v", err)
+		} else if processed > 0 {
+			log.Printf("Achievement outbox worker processed %d entries", processed)
+		}
+		<-ticker.C
+	}
+}
+
+// ProcessAchievementOutbox memproses paling banyak limit entri outbox yang jatuh tempo dan
+// mengembalikan jumlah entri yang diproses. Pembuatan prestasi yang tidak pernah mendapat
+// reference dikompensasi dengan menghapus dokumen MongoDB; penghapusan prestasi dan pembaruan
+// poin saat verifikasi diulang sampai dokumen MongoDB ikut diperbarui.
+func ProcessAchievementOutbox(postgresDB *sql.DB, mongoDB *mongo.Database, limit int) (int, error) {
+	apply := func(entry modelpostgre.AchievementOutbox, referenceExists bool) (string, error) {
+		return applyAchievementOutbox(postgresDB, mongoDB, entry, referenceExists)
+	}
+
+	processed := 0
+	for processed < limit {
+		ok, err := repositorypostgre.ProcessNextAchievementOutbox(postgresDB, achievementOutboxMaxAttempts, apply)
+		if err != nil {
+			return processed, err
+		}
+		if !ok {
+			break
+		}
+		processed++
+	}
+
+	return processed, nil
+}
+
+func applyAchievementOutbox(postgresDB *sql.DB, mongoDB *mongo.Database, entry modelpostgre.AchievementOutbox, referenceExists bool) (string, error) {
+	switch entry.Operation {
+	case modelpostgre.AchievementOutboxOperationCreate:
+		if referenceExists {
+			return modelpostgre.AchievementOutboxStatusCompleted, nil
+		}
+		if err := repositorymongo.PurgeAchievement(mongoDB, entry.MongoAchievementID); err != nil {
+			return "", err
+		}
+		return modelpostgre.AchievementOutboxStatusCompensated, nil
+	case modelpostgre.AchievementOutboxOperationDelete:
+		if err := repositorymongo.DeleteAchievement(mongoDB, entry.MongoAchievementID); err != nil {
+			return "", err
+		}
+		return modelpostgre.AchievementOutboxStatusCompleted, nil
+	case modelpostgre.AchievementOutboxOperationPoints:
+		// Nilai yang dihitung saat verifikasi tidak disimpan, sehingga poin dihitung ulang dengan
+		// rubrik yang sedang aktif.
+		achievement, err := repositorymongo.GetAchievementByID(mongoDB, entry.MongoAchievementID)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return modelpostgre.AchievementOutboxStatusCompleted, nil
+			}
+			return "", err
+		}
+		points, pointRubricVersion, err := calculateAchievementPoints(postgresDB, achievement.AchievementType, achievement.Details)
+		if err != nil {
+			return "", err
+		}
+		if err := repositorymongo.UpdateAchievementPoints(mongoDB, entry.MongoAchievementID, points, pointRubricVersion); err != nil {
+			return "", err
+		}
+		return modelpostgre.AchievementOutboxStatusCompleted, nil
+	}
+
+	return "", fmt.Errorf("unknown achievement outbox operation: %s", entry.Operation)
+}
+
+// checkAchievementIdempotencyKey memeriksa pembuatan prestasi sebelumnya dengan Idempotency-Key
+// yang sama. Jika pembuatan sebelumnya sudah selesai, prestasi tersebut dikirim ulang dan
+// nilai false dikembalikan; entri yang sudah dikompensasi dihapus agar key dapat dipakai lagi.
+func checkAchievementIdempotencyKey(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.Database, userID string, idempotencyKey string) (bool, error) {
+	if len(idempotencyKey) > 100 {
+		return false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Idempotency-Key maksimal 100 karakter.",
+			},
+		})
+	}
+
+	outbox, err := repositorypostgre.GetAchievementOutboxByIdempotencyKey(postgresDB, userID, idempotencyKey)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return true, nil
+		}
+		return false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error memeriksa Idempotency-Key. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	switch outbox.Status {
+	case modelpostgre.AchievementOutboxStatusCompleted:
+		achievement, err := repositorymongo.GetAchievementByID(mongoDB, outbox.MongoAchievementID)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return false, helper.ConflictResponse(c, "Prestasi untuk Idempotency-Key ini sudah dihapus.")
+			}
+			return false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Error mengambil prestasi dari MongoDB. Detail: " + err.Error(),
+				},
+			})
+		}
+
+		response := modelmongo.CreateAchievementResponse{
+			Status: "success",
+			Data:   *achievement,
+		}
+		return false, c.Status(fiber.StatusOK).JSON(response)
+	case modelpostgre.AchievementOutboxStatusCompensated:
+		if err := repositorypostgre.DeleteAchievementOutbox(postgresDB, outbox.ID); err != nil {
+			return false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Error membersihkan outbox prestasi. Detail: " + err.Error(),
+				},
+			})
+		}
+		return true, nil
+	case modelpostgre.AchievementOutboxStatusFailed:
+		return false, helper.ConflictResponse(c, "Pembuatan prestasi dengan Idempotency-Key ini gagal diselesaikan. Gunakan Idempotency-Key baru.")
+	}
+
+	return false, helper.ConflictResponse(c, "Request dengan Idempotency-Key yang sama sedang diproses.")
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/helper"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GetAchievementParticipantsService menampilkan peserta prestasi tim. Sebelum prestasi
+// diverifikasi, estimated_points berisi perkiraan bagian poin dari poin dan rubrik saat ini.
+func GetAchievementParticipantsService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.Database) error {
+	ref, ok, err := getReadableAchievementReference(c, postgresDB)
+	if !ok {
+		return err
+	}
+
+	participants, err := repositorypostgre.GetAchievementParticipants(postgresDB, ref.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil peserta prestasi. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	if len(participants) > 0 && ref.Status != modelpostgre.AchievementStatusVerified {
+		achievement, err := repositorymongo.GetAchievementByID(mongoDB, ref.MongoAchievementID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Error mengambil achievement dari database. Detail: " + err.Error(),
+				},
+			})
+		}
+
+		shares, err := splitParticipantPoints(postgresDB, participants, achievement.AchievementType, achievement.Points)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Error menghitung pembagian poin peserta prestasi. Detail: " + err.Error(),
+				},
+			})
+		}
+		for i := range participants {
+			estimated := shares[i]
+			participants[i].EstimatedPoints = &estimated
+		}
+	}
+
+	response := modelpostgre.GetAchievementParticipantsResponse{
+		Status: "success",
+		Data:   participants,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// SetAchievementParticipantsService mengganti peserta prestasi tim. Hanya user yang dapat
+// mengupdate prestasi yang boleh mengubah peserta, dan pemilik prestasi harus termasuk peserta.
+func SetAchievementParticipantsService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.Database) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			},
+		})
+	}
+
+	policy, ok, err := resolveAchievementPolicy(c, postgresDB, userID, modelpostgre.AchievementActionWrite)
+	if !ok {
+		return err
+	}
+
+	ref, err := repositorypostgre.GetAchievementReferenceByMongoID(postgresDB, c.Params("id"))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Prestasi tidak ditemukan.",
+				},
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil data prestasi dari database. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	if !modelpostgre.IsAchievementEditable(ref.Status) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Peserta prestasi hanya dapat diubah jika status adalah draft atau rejected.",
+			},
+		})
+	}
+
+	if ok, err := authorizeAchievementStudent(c, postgresDB, policy, ref.StudentID, "Akses ditolak. Anda hanya dapat mengubah peserta prestasi milik Anda sendiri."); !ok {
+		return err
+	}
+
+	var req modelpostgre.SetAchievementParticipantsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Format request body tidak valid. Pastikan JSON format benar. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	if fieldErrors := validateAchievementParticipants(req.Participants, ref.StudentID); len(fieldErrors) > 0 {
+		return helper.FieldErrorsResponse(c, "Validasi peserta prestasi gagal.", fieldErrors)
+	}
+
+	if len(req.Participants) > 0 {
+		studentIDs := make([]string, len(req.Participants))
+		for i, participant := range req.Participants {
+			studentIDs[i] = participant.StudentID
+		}
+		count, err := repositorypostgre.CountExistingStudents(postgresDB, studentIDs)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Error mengambil data mahasiswa dari database. Detail: " + err.Error(),
+				},
+			})
+		}
+		if count != len(studentIDs) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Sebagian data mahasiswa peserta tidak ditemukan.",
+				},
+			})
+		}
+	}
+
+	if err := repositorypostgre.SetAchievementParticipants(postgresDB, ref.ID, req.Participants, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Status prestasi sudah berubah. Muat ulang data prestasi.",
+				},
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error menyimpan peserta prestasi. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	return GetAchievementParticipantsService(c, postgresDB, mongoDB)
+}
+
+func validateAchievementParticipants(participants []modelpostgre.AchievementParticipantInput, ownerStudentID string) []modelmongo.FieldError {
+	fieldErrors := []modelmongo.FieldError{}
+	if len(participants) == 0 {
+		return fieldErrors
+	}
+
+	if len(participants) > modelpostgre.MaxAchievementParticipants {
+		return append(fieldErrors, modelmongo.FieldError{Field: "participants", Message: "Jumlah peserta maksimal " + strconv.Itoa(modelpostgre.MaxAchievementParticipants) + " mahasiswa."})
+	}
+
+	seen := make(map[string]bool)
+	leaders := 0
+	ownerIncluded := false
+	for i, participant := range participants {
+		field := fmt.Sprintf("participants[%d]", i)
+		if !helper.IsValidUUID(participant.StudentID) {
+			fieldErrors = append(fieldErrors, modelmongo.FieldError{Field: field + ".student_id", Message: "Student ID wajib diisi dengan UUID yang valid."})
+		} else if seen[participant.StudentID] {
+			fieldErrors = append(fieldErrors, modelmongo.FieldError{Field: field + ".student_id", Message: "Mahasiswa yang sama tidak boleh didaftarkan dua kali."})
+		}
+		seen[participant.StudentID] = true
+
+		if participant.StudentID == ownerStudentID {
+			ownerIncluded = true
+		}
+
+		if !modelpostgre.IsValidParticipantRole(participant.Role) {
+			fieldErrors = append(fieldErrors, modelmongo.FieldError{Field: field + ".role", Message: "Role peserta tidak valid. Gunakan: leader atau member."})
+		} else if participant.Role == modelpostgre.ParticipantRoleLeader {
+			leaders++
+		}
+	}
+
+	if leaders != 1 {
+		fieldErrors = append(fieldErrors, modelmongo.FieldError{Field: "participants", Message: "Prestasi tim harus memiliki tepat satu leader."})
+	}
+	if !ownerIncluded {
+		fieldErrors = append(fieldErrors, modelmongo.FieldError{Field: "participants", Message: "Mahasiswa pemilik prestasi harus termasuk peserta."})
+	}
+
+	return fieldErrors
+}
+
+// calculateParticipantPoints menghitung bagian poin setiap peserta prestasi tim untuk disimpan
+// saat verifikasi. Mengembalikan nil untuk prestasi individu.
+func calculateParticipantPoints(postgresDB *sql.DB, referenceID string, achievementType string, points int) (map[string]int, error) {
+	participants, err := repositorypostgre.GetAchievementParticipants(postgresDB, referenceID)
+	if err != nil || len(participants) == 0 {
+		return nil, err
+	}
+
+	shares, err := splitParticipantPoints(postgresDB, participants, achievementType, points)
+	if err != nil {
+		return nil, err
+	}
+
+	participantPoints := make(map[string]int, len(participants))
+	for i, participant := range participants {
+		participantPoints[participant.StudentID] = shares[i]
+	}
+	return participantPoints, nil
+}
+
+// splitParticipantPoints membagi poin dengan aturan pembagian tim pada rubrik yang sedang
+// diterbitkan. Tanpa rubrik aktif, setiap peserta mendapat poin penuh.
+func splitParticipantPoints(postgresDB *sql.DB, participants []modelpostgre.AchievementParticipant, achievementType string, points int) ([]int, error) {
+	rubric, err := repositorypostgre.GetPointRubricByStatus(postgresDB, modelpostgre.PointRubricStatusPublished)
+	if err == sql.ErrNoRows {
+		rubric, err = &modelpostgre.PointRubric{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]string, len(participants))
+	for i, participant := range participants {
+		roles[i] = participant.Role
+	}
+
+	return rubric.SplitPoints(achievementType, points, roles), nil
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"database/sql"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// achievementPolicy menyimpan scope akses user untuk satu aksi prestasi beserta
+// profil yang dibutuhkan untuk memeriksa kepemilikan.
+type achievementPolicy struct {
+	UserID     string
+	Scope      string
+	StudentID  string
+	LecturerID string
+}
+
+// resolveAchievementPolicy menentukan scope terluas yang dimiliki user untuk aksi prestasi
+// berdasarkan permission achievement:<aksi>:<scope>. Jika ok bernilai false, response error sudah ditulis.
+func resolveAchievementPolicy(c *fiber.Ctx, postgresDB *sql.DB, userID string, action string) (*achievementPolicy, bool, error) {
+	permissions, err := repositorypostgre.GetUserPermissions(postgresDB, userID)
+	if err != nil {
+		return nil, false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil permission user. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	granted := make(map[string]bool)
+	for _, permission := range permissions {
+		granted[permission] = true
+	}
+
+	policy := &achievementPolicy{UserID: userID}
+	for _, scope := range modelpostgre.AchievementScopes {
+		if granted[modelpostgre.AchievementScopePermission(action, scope)] {
+			policy.Scope = scope
+			break
+		}
+	}
+
+	switch policy.Scope {
+	case modelpostgre.AchievementScopeOwn:
+		studentID, err := repositorypostgre.GetStudentIDByUserID(postgresDB, userID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, false, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"status": "error",
+					"data": fiber.Map{
+						"message": "Data mahasiswa tidak ditemukan. Pastikan user memiliki profil mahasiswa.",
+					},
+				})
+			}
+			return nil, false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Error mengambil data mahasiswa dari database. Detail: " + err.Error(),
+				},
+			})
+		}
+		policy.StudentID = studentID
+	case modelpostgre.AchievementScopeAdvisees:
+		lecturer, err := repositorypostgre.GetLecturerByUserID(postgresDB, userID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, false, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"status": "error",
+					"data": fiber.Map{
+						"message": "Data dosen wali tidak ditemukan. Pastikan user memiliki profil dosen wali.",
+					},
+				})
+			}
+			return nil, false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Error mengambil data dosen wali. Detail: " + err.Error(),
+				},
+			})
+		}
+		policy.LecturerID = lecturer.ID
+	case modelpostgre.AchievementScopeAll:
+	default:
+		return nil, false, c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Akses ditolak. Anda tidak memiliki scope '" + modelpostgre.AchievementScopePermission(action, "*") + "' untuk prestasi.",
+			},
+		})
+	}
+
+	return policy, true, nil
+}
+
+// authorizeAchievementStudent memastikan prestasi milik studentID berada dalam scope policy.
+// Jika ok bernilai false, response error sudah ditulis.
+func authorizeAchievementStudent(c *fiber.Ctx, postgresDB *sql.DB, policy *achievementPolicy, studentID string, deniedMessage string) (bool, error) {
+	allowed, err := isAchievementStudentInScope(postgresDB, policy, studentID)
+	if err != nil {
+		return false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil data student. Detail: " + err.Error(),
+			},
+		})
+	}
+	if allowed {
+		return true, nil
+	}
+
+	return false, c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"status": "error",
+		"data": fiber.Map{
+			"message": deniedMessage,
+		},
+	})
+}
+
+// authorizeAchievementReader seperti authorizeAchievementStudent untuk aksi baca, tetapi juga
+// mengizinkan peserta prestasi tim dan dosen wali masing-masing peserta.
+// Jika ok bernilai false, response error sudah ditulis.
+func authorizeAchievementReader(c *fiber.Ctx, postgresDB *sql.DB, policy *achievementPolicy, ref *modelpostgre.AchievementReference, deniedMessage string) (bool, error) {
+	allowed, err := isAchievementStudentInScope(postgresDB, policy, ref.StudentID)
+	if err == nil && !allowed {
+		allowed, err = repositorypostgre.IsAchievementParticipantInScope(postgresDB, ref.ID, policy.StudentID, policy.LecturerID)
+	}
+	if err != nil {
+		return false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error memeriksa akses prestasi. Detail: " + err.Error(),
+			},
+		})
+	}
+	if allowed {
+		return true, nil
+	}
+
+	return false, c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"status": "error",
+		"data": fiber.Map{
+			"message": deniedMessage,
+		},
+	})
+}
+
+func isAchievementStudentInScope(postgresDB *sql.DB, policy *achievementPolicy, studentID string) (bool, error) {
+	switch policy.Scope {
+	case modelpostgre.AchievementScopeAll:
+		return true, nil
+	case modelpostgre.AchievementScopeOwn:
+		return studentID == policy.StudentID, nil
+	case modelpostgre.AchievementScopeAdvisees:
+		student, err := repositorypostgre.GetStudentByID(postgresDB, studentID)
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return student.AdvisorID == policy.LecturerID, nil
+	}
+	return false, nil
+}
+
+// scopeAchievementReferenceFilter membatasi filter reference prestasi sesuai scope policy.
+func scopeAchievementReferenceFilter(policy *achievementPolicy, filter *modelpostgre.AchievementReferenceFilter) {
+	switch policy.Scope {
+	case modelpostgre.AchievementScopeOwn:
+		filter.ScopeStudentID = policy.StudentID
+	case modelpostgre.AchievementScopeAdvisees:
+		filter.AdvisorID = policy.LecturerID
+	}
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const reconcileNote = "Diperbaiki otomatis oleh rekonsiliasi: dokumen MongoDB tidak aktif."
+
+// reconcileMaxOrphanRatio adalah batas proporsi reference aktif tanpa dokumen MongoDB yang masih
+// diperbaiki otomatis. Di atas batas ini --fix dibatalkan karena kemungkinan besar perintah
+// terhubung ke database MongoDB yang salah atau kosong, bukan karena data benar-benar hilang.
+const reconcileMaxOrphanRatio = 0.5
+
+// ReconcileAchievements membandingkan koleksi achievements dengan tabel achievement_references dan
+// melaporkan ketidakcocokan. Dokumen yang masih memiliki operasi outbox pending atau dibuat kurang
+// dari gracePeriod yang lalu dilewati karena mungkin masih diproses. Jika fix bernilai true,
+// perbaikan aman diterapkan: PostgreSQL dianggap sumber kebenaran untuk kepemilikan, dan
+// penghapusan selalu berupa soft delete atau status deleted sehingga data tidak hilang. Reference
+// verified yang dokumennya hilang hanya dilaporkan untuk diperiksa manual.
+func ReconcileAchievements(postgresDB *sql.DB, mongoDB *mongo.Database, fix bool, gracePeriod time.Duration) (*modelpostgre.ReconcileReport, error) {
+	documents, err := repositorymongo.GetAchievementSyncStates(mongoDB)
+	if err != nil {
+		return nil, err
+	}
+
+	references, err := repositorypostgre.GetAllAchievementReferencesWithDeleted(postgresDB)
+	if err != nil {
+		return nil, err
+	}
+
+	inFlight, err := repositorypostgre.GetPendingAchievementOutboxMongoIDs(postgresDB)
+	if err != nil {
+		return nil, err
+	}
+
+	documentMap := make(map[string]modelmongo.Achievement, len(documents))
+	for _, document := range documents {
+		documentMap[document.ID.Hex()] = document
+	}
+
+	if fix {
+		activeReferences, orphanReferences := 0, 0
+		for _, ref := range references {
+			if ref.Status == modelpostgre.AchievementStatusDeleted || inFlight[ref.MongoAchievementID] {
+				continue
+			}
+			activeReferences++
+			if _, exists := documentMap[ref.MongoAchievementID]; !exists {
+				orphanReferences++
+			}
+		}
+		if activeReferences > 0 && (len(documents) == 0 || float64(orphanReferences) > float64(activeReferences)*reconcileMaxOrphanRatio) {
+			return nil, fmt.Errorf(
+				"--fix dibatalkan: %d dari %d reference aktif tidak memiliki dokumen di MongoDB (%d dokumen dipindai). Periksa konfigurasi MONGODB_URI dan MONGODB_DATABASE, lalu jalankan tanpa --fix untuk melihat laporannya",
+				orphanReferences, activeReferences, len(documents),
+			)
+		}
+	}
+
+	report := &modelpostgre.ReconcileReport{
+		GeneratedAt:       time.Now(),
+		DryRun:            !fix,
+		ScannedDocuments:  len(documents),
+		ScannedReferences: len(references),
+		Summary:           make(map[string]int),
+		Issues:            []modelpostgre.ReconcileIssue{},
+	}
+	for _, issueType := range modelpostgre.ReconcileIssueTypes {
+		report.Summary[issueType] = 0
+	}
+
+	referencedIDs := make(map[string]bool, len(references))
+	cutoff := time.Now().Add(-gracePeriod)
+
+	for _, ref := range references {
+		referencedIDs[ref.MongoAchievementID] = true
+		if inFlight[ref.MongoAchievementID] {
+			report.SkippedInFlight++
+			continue
+		}
+
+		refID := ref.ID
+		issue := modelpostgre.ReconcileIssue{
+			MongoAchievementID:     ref.MongoAchievementID,
+			AchievementReferenceID: &refID,
+			ReferenceStatus:        ref.Status,
+			PostgresStudentID:      ref.StudentID,
+		}
+		referenceDeleted := ref.Status == modelpostgre.AchievementStatusDeleted
+		markDeleted := func() error {
+			return markReferenceDeletedForReconcile(postgresDB, ref.ID)
+		}
+		// Prestasi verified bisa sudah tercantum di transkrip, sehingga tidak ditandai deleted otomatis.
+		if ref.Status == modelpostgre.AchievementStatusVerified {
+			markDeleted = nil
+		}
+
+		document, exists := documentMap[ref.MongoAchievementID]
+		if !exists {
+			if referenceDeleted {
+				continue
+			}
+			issue.Type = modelpostgre.ReconcileIssueReferenceOrphan
+			issue.Repair = reconcileReferenceRepair(markDeleted)
+			addReconcileIssue(report, issue, fix, markDeleted)
+			continue
+		}
+
+		issue.MongoStudentID = document.StudentID
+		documentDeleted := document.DeletedAt != nil
+
+		if document.StudentID != ref.StudentID {
+			mismatch := issue
+			mismatch.Type = modelpostgre.ReconcileIssueStudentMismatch
+			mismatch.Repair = "Samakan studentId dokumen dengan student_id reference."
+			addReconcileIssue(report, mismatch, fix, func() error {
+				return repositorymongo.UpdateAchievementStudentID(mongoDB, ref.MongoAchievementID, ref.StudentID)
+			})
+		}
+
+		switch {
+		case documentDeleted && !referenceDeleted:
+			issue.Type = modelpostgre.ReconcileIssueDeletedDocument
+			issue.Repair = reconcileReferenceRepair(markDeleted)
+			addReconcileIssue(report, issue, fix, markDeleted)
+		case referenceDeleted && !documentDeleted:
+			issue.Type = modelpostgre.ReconcileIssueDeletedReference
+			issue.Repair = "Soft delete dokumen MongoDB."
+			addReconcileIssue(report, issue, fix, func() error {
+				return repositorymongo.DeleteAchievement(mongoDB, ref.MongoAchievementID)
+			})
+		}
+	}
+
+	for _, document := range documents {
+		mongoID := document.ID.Hex()
+		if referencedIDs[mongoID] || document.DeletedAt != nil {
+			continue
+		}
+		if inFlight[mongoID] || document.CreatedAt.After(cutoff) {
+			report.SkippedInFlight++
+			continue
+		}
+
+		issue := modelpostgre.ReconcileIssue{
+			Type:               modelpostgre.ReconcileIssueDocumentOrphan,
+			MongoAchievementID: mongoID,
+			MongoStudentID:     document.StudentID,
+			Repair:             "Soft delete dokumen MongoDB.",
+		}
+		addReconcileIssue(report, issue, fix, func() error {
+			return repositorymongo.DeleteAchievement(mongoDB, mongoID)
+		})
+	}
+
+	return report, nil
+}
+
+func markReferenceDeletedForReconcile(postgresDB *sql.DB, referenceID string) error {
+	err := repositorypostgre.MarkAchievementReferenceDeleted(postgresDB, referenceID, reconcileNote)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	return err
+}
+
+func reconcileReferenceRepair(markDeleted func() error) string {
+	if markDeleted == nil {
+		return "Periksa manual: reference verified tidak ditandai deleted otomatis."
+	}
+	return "Tandai reference sebagai deleted."
+}
+
+// addReconcileIssue mencatat masalah ke laporan dan menjalankan repair jika fix bernilai true.
+// repair bernilai nil untuk masalah yang hanya boleh diperbaiki manual.
+func addReconcileIssue(report *modelpostgre.ReconcileReport, issue modelpostgre.ReconcileIssue, fix bool, repair func() error) {
+	if fix && repair != nil {
+		if err := repair(); err != nil {
+			issue.FixError = err.Error()
+		} else {
+			issue.Fixed = true
+		}
+	}
+
+	report.Summary[issue.Type]++
+	report.Issues = append(report.Issues, issue)
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"database/sql"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// recordAchievementRevision mencatat isi updated sebagai revisi baru sebelum dokumen prestasi
+// diupdate. Prestasi lama yang belum memiliki revisi mendapat revisi awal dari isi existing
+// terlebih dahulu agar perubahan pertama tetap dapat dibandingkan.
+func recordAchievementRevision(mongoDB *mongo.Database, existing modelmongo.Achievement, updated modelmongo.Achievement, previousStatus string, userID string) (*modelmongo.AchievementRevision, error) {
+	_, err := repositorymongo.GetLatestAchievementRevision(mongoDB, existing.ID.Hex())
+	if err == mongo.ErrNoDocuments {
+		_, err = repositorymongo.CreateAchievementRevision(mongoDB, modelmongo.AchievementRevision{
+			AchievementID: existing.ID,
+			Snapshot:      modelmongo.NewAchievementSnapshot(existing),
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return repositorymongo.CreateAchievementRevision(mongoDB, modelmongo.AchievementRevision{
+		AchievementID:  existing.ID,
+		PreviousStatus: previousStatus,
+		EditedBy:       userID,
+		Snapshot:       modelmongo.NewAchievementSnapshot(updated),
+	})
+}
+
+func GetAchievementRevisionsService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.Database) error {
+	ref, ok, err := getReadableAchievementReference(c, postgresDB)
+	if !ok {
+		return err
+	}
+
+	revisions, err := repositorymongo.GetAchievementRevisions(mongoDB, ref.MongoAchievementID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil revisi prestasi. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := modelmongo.GetAchievementRevisionsResponse{
+		Status: "success",
+		Data:   revisions,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetAchievementRevisionDiffService membandingkan revisi from dan to. Tanpa query parameter,
+// revisi terakhir dibandingkan dengan revisi sebelumnya; to tanpa from dibandingkan dengan to-1.
+func GetAchievementRevisionDiffService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.Database) error {
+	ref, ok, err := getReadableAchievementReference(c, postgresDB)
+	if !ok {
+		return err
+	}
+
+	badRequest := func(message string) error {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": message,
+			},
+		})
+	}
+
+	parseRevision := func(param string) (int, bool) {
+		value := c.Query(param)
+		if value == "" {
+			return 0, true
+		}
+		revision, err := strconv.Atoi(value)
+		if err != nil || revision < 1 {
+			return 0, false
+		}
+		return revision, true
+	}
+
+	fromNumber, ok := parseRevision("from")
+	if !ok {
+		return badRequest("Parameter from harus berupa nomor revisi (angka minimal 1).")
+	}
+	toNumber, ok := parseRevision("to")
+	if !ok {
+		return badRequest("Parameter to harus berupa nomor revisi (angka minimal 1).")
+	}
+
+	if toNumber == 0 {
+		latest, err := repositorymongo.GetLatestAchievementRevision(mongoDB, ref.MongoAchievementID)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Error mengambil revisi prestasi. Detail: " + err.Error(),
+				},
+			})
+		}
+		if latest != nil {
+			toNumber = latest.Revision
+		}
+	}
+	if fromNumber == 0 {
+		fromNumber = toNumber - 1
+	}
+
+	if fromNumber < 1 || toNumber < 1 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Prestasi belum memiliki dua revisi untuk dibandingkan.",
+			},
+		})
+	}
+	if fromNumber == toNumber {
+		return badRequest("Parameter from dan to harus berbeda.")
+	}
+
+	revisions := make([]*modelmongo.AchievementRevision, 2)
+	for i, number := range []int{fromNumber, toNumber} {
+		revision, err := repositorymongo.GetAchievementRevision(mongoDB, ref.MongoAchievementID, number)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"status": "error",
+					"data": fiber.Map{
+						"message": "Revisi " + strconv.Itoa(number) + " tidak ditemukan.",
+					},
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Error mengambil revisi prestasi. Detail: " + err.Error(),
+				},
+			})
+		}
+		revisions[i] = revision
+	}
+
+	changes, err := modelmongo.DiffAchievementSnapshots(revisions[0].Snapshot, revisions[1].Snapshot)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error membandingkan revisi prestasi. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := modelmongo.GetAchievementRevisionDiffResponse{
+		Status: "success",
+		Data: modelmongo.AchievementRevisionDiff{
+			AchievementID: ref.MongoAchievementID,
+			From:          *revisions[0],
+			To:            *revisions[1],
+			Changes:       changes,
+		},
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"database/sql"
 	"fmt"
+	"log"
 	"mime"
 	"os"
 	"path/filepath"
@@ -10,10 +11,14 @@ import (
 	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
 	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
 	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/helper"
+	utilspostgre "sistem-pelaporan-prestasi-mahasiswa/utils/postgre"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -28,51 +33,16 @@ func CreateAchievementService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.D
 		})
 	}
 
-	roleID, ok := c.Locals("role_id").(string)
+	policy, ok, err := resolveAchievementPolicy(c, postgresDB, userID, modelpostgre.AchievementActionWrite)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "Role ID tidak ditemukan. Silakan login ulang.",
-			},
-		})
-	}
-
-	roleName, err := repositorypostgre.GetRoleName(postgresDB, roleID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "Error mengambil role name. Detail: " + err.Error(),
-			},
-		})
-	}
-
-	if roleName != "Mahasiswa" {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "Akses ditolak. Hanya mahasiswa yang dapat membuat prestasi.",
-			},
-		})
+		return err
 	}
 
-	studentID, err := repositorypostgre.GetStudentIDByUserID(postgresDB, userID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status": "error",
-				"data": fiber.Map{
-					"message": "Data mahasiswa tidak ditemukan. Pastikan user memiliki profil mahasiswa.",
-				},
-			})
+	idempotencyKey := strings.TrimSpace(c.Get("Idempotency-Key"))
+	if idempotencyKey != "" {
+		if ok, err := checkAchievementIdempotencyKey(c, postgresDB, mongoDB, userID, idempotencyKey); !ok {
+			return err
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "Error mengambil data mahasiswa dari database. Detail: " + err.Error(),
-			},
-		})
 	}
 
 	var req modelmongo.CreateAchievementRequest
@@ -85,101 +55,100 @@ func CreateAchievementService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.D
 		})
 	}
 
-	if req.AchievementType == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "Achievement type wajib diisi.",
-			},
-		})
-	}
-
-	if req.Title == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "Title wajib diisi.",
-			},
-		})
+	if fieldErrors := modelmongo.ValidateCreateAchievementRequest(req); len(fieldErrors) > 0 {
+		return helper.FieldErrorsResponse(c, "Validasi prestasi gagal.", fieldErrors)
 	}
 
-	if req.Description == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "Description wajib diisi.",
-			},
-		})
-	}
+	if policy.Scope == modelpostgre.AchievementScopeOwn {
+		req.StudentID = policy.StudentID
+	} else {
+		if !helper.IsValidUUID(req.StudentID) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Student ID wajib diisi dengan UUID yang valid saat membuat prestasi untuk mahasiswa lain.",
+				},
+			})
+		}
 
-	validTypes := map[string]bool{
-		"academic":      true,
-		"competition":   true,
-		"organization":  true,
-		"publication":   true,
-		"certification": true,
-		"other":         true,
-	}
+		if _, err := repositorypostgre.GetStudentByID(postgresDB, req.StudentID); err != nil {
+			if err == sql.ErrNoRows {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"status": "error",
+					"data": fiber.Map{
+						"message": "Data mahasiswa tidak ditemukan.",
+					},
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Error mengambil data mahasiswa dari database. Detail: " + err.Error(),
+				},
+			})
+		}
 
-	if !validTypes[req.AchievementType] {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "Achievement type tidak valid. Gunakan: academic, competition, organization, publication, certification, atau other.",
-			},
-		})
+		if ok, err := authorizeAchievementStudent(c, postgresDB, policy, req.StudentID, "Akses ditolak. Anda hanya dapat membuat prestasi untuk mahasiswa bimbingan Anda."); !ok {
+			return err
+		}
 	}
 
-	req.StudentID = studentID
-
-	achievement := modelmongo.Achievement{
-		StudentID:       req.StudentID,
-		AchievementType: req.AchievementType,
-		Title:           req.Title,
-		Description:     req.Description,
-		Details:         req.Details,
-		Attachments:     req.Attachments,
-		Tags:            req.Tags,
-		Points:          req.Points,
-		CreatedAt:       time.Now(),
-		UpdatedAt:       time.Now(),
-	}
-
-	createdAchievement, err := repositorymongo.CreateAchievement(mongoDB, achievement)
+	points, pointRubricVersion, err := calculateAchievementPoints(postgresDB, req.AchievementType, req.Details)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status": "error",
 			"data": fiber.Map{
-				"message": "Error menyimpan prestasi ke database. Detail: " + err.Error(),
+				"message": "Error menghitung poin prestasi. Detail: " + err.Error(),
 			},
 		})
 	}
 
-	refReq := modelpostgre.CreateAchievementReferenceRequest{
-		StudentID:          studentID,
-		MongoAchievementID: createdAchievement.ID.Hex(),
-		Status:             modelpostgre.AchievementStatusDraft,
-	}
+	fillAttachmentChecksums(req.Attachments)
 
-	_, err = repositorypostgre.CreateAchievementReference(postgresDB, refReq)
+	achievement := modelmongo.Achievement{
+		ID:                 primitive.NewObjectID(),
+		StudentID:          req.StudentID,
+		AchievementType:    req.AchievementType,
+		Title:              req.Title,
+		Description:        req.Description,
+		Details:            req.Details,
+		Attachments:        req.Attachments,
+		Tags:               req.Tags,
+		Points:             points,
+		PointRubricVersion: pointRubricVersion,
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
+	}
+
+	var idempotencyKeyPtr *string
+	if idempotencyKey != "" {
+		idempotencyKeyPtr = &idempotencyKey
+	}
+
+	creation, err := createAchievementWithOutbox(postgresDB, mongoDB, achievement, modelpostgre.AchievementStatusDraft, userID, idempotencyKeyPtr)
 	if err != nil {
+		if err == errAchievementCreationInProgress {
+			return helper.ConflictResponse(c, "Request dengan Idempotency-Key yang sama sedang diproses.")
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status": "error",
 			"data": fiber.Map{
-				"message": "Error membuat reference prestasi. Detail: " + err.Error(),
+				"message": err.Error(),
 			},
 		})
 	}
 
+	// Pemeriksaan duplikat hanya berupa peringatan dan tidak membatalkan pembuatan prestasi.
 	response := modelmongo.CreateAchievementResponse{
 		Status: "success",
-		Data:   *createdAchievement,
+		Data:   *creation.Achievement,
 	}
+	response.DuplicateCount, response.DuplicateReasons = summarizeDuplicateFlags(creation.DuplicateFlags)
 
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
-func SubmitAchievementService(c *fiber.Ctx, postgresDB *sql.DB) error {
+func SubmitAchievementService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.Database) error {
 	userID, ok := c.Locals("user_id").(string)
 	if !ok {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
@@ -190,33 +159,9 @@ func SubmitAchievementService(c *fiber.Ctx, postgresDB *sql.DB) error {
 		})
 	}
 
-	roleID, ok := c.Locals("role_id").(string)
+	policy, ok, err := resolveAchievementPolicy(c, postgresDB, userID, modelpostgre.AchievementActionWrite)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "Role ID tidak ditemukan. Silakan login ulang.",
-			},
-		})
-	}
-
-	roleName, err := repositorypostgre.GetRoleName(postgresDB, roleID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "Error mengambil role name. Detail: " + err.Error(),
-			},
-		})
-	}
-
-	if roleName != "Mahasiswa" {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "Akses ditolak. Hanya mahasiswa yang dapat submit prestasi.",
-			},
-		})
+		return err
 	}
 
 	mongoID := c.Params("id")
@@ -247,37 +192,30 @@ func SubmitAchievementService(c *fiber.Ctx, postgresDB *sql.DB) error {
 		})
 	}
 
-	if ref.Status != modelpostgre.AchievementStatusDraft {
+	if !modelpostgre.CanTransitionAchievementStatus(ref.Status, modelpostgre.AchievementStatusSubmitted) {
 		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
 			"status": "error",
 			"data": fiber.Map{
-				"message": "Prestasi hanya dapat di-submit jika status adalah draft.",
-			},
-		})
-	}
-
-	studentID, err := repositorypostgre.GetStudentIDByUserID(postgresDB, userID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "Error mengambil data mahasiswa. Detail: " + err.Error(),
+				"message": "Prestasi hanya dapat di-submit jika status adalah draft atau rejected.",
 			},
 		})
 	}
 
-	if ref.StudentID != studentID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "Akses ditolak. Anda hanya dapat submit prestasi milik Anda sendiri.",
-			},
-		})
+	if ok, err := authorizeAchievementStudent(c, postgresDB, policy, ref.StudentID, "Akses ditolak. Anda hanya dapat submit prestasi milik Anda sendiri."); !ok {
+		return err
 	}
 
 	now := time.Now()
-	err = repositorypostgre.UpdateAchievementReferenceStatus(postgresDB, ref.ID, modelpostgre.AchievementStatusSubmitted, &now)
+	err = repositorypostgre.UpdateAchievementReferenceStatus(postgresDB, ref.ID, modelpostgre.AchievementStatusSubmitted, &now, userID)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Status prestasi sudah berubah. Muat ulang data prestasi.",
+				},
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status": "error",
 			"data": fiber.Map{
@@ -296,10 +234,22 @@ func SubmitAchievementService(c *fiber.Ctx, postgresDB *sql.DB) error {
 		})
 	}
 
-	response := modelpostgre.UpdateAchievementReferenceResponse{
+	// Pemeriksaan ulang saat submit menandai duplikat untuk dosen wali, termasuk prestasi lain
+	// yang dibuat setelah prestasi ini. Kegagalan tidak membatalkan submit.
+	duplicateFlags := []modelpostgre.AchievementDuplicateFlag{}
+	if achievement, err := repositorymongo.GetAchievementByID(mongoDB, ref.MongoAchievementID); err != nil {
+		log.Printf("Failed to load achievement %s for duplicate check: %v", ref.MongoAchievementID, err)
+	} else if _, flags, err := flagAchievementDuplicates(postgresDB, mongoDB, ref.ID, *achievement); err != nil {
+		log.Printf("Failed to check duplicates for achievement %s: %v", ref.MongoAchievementID, err)
+	} else {
+		duplicateFlags = flags
+	}
+
+	response := modelpostgre.SubmitAchievementResponse{
 		Status: "success",
 		Data:   *updatedRef,
 	}
+	response.DuplicateCount, response.DuplicateReasons = summarizeDuplicateFlags(duplicateFlags)
 
 	return c.Status(fiber.StatusOK).JSON(response)
 }
@@ -315,33 +265,9 @@ func DeleteAchievementService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.D
 		})
 	}
 
-	roleID, ok := c.Locals("role_id").(string)
+	policy, ok, err := resolveAchievementPolicy(c, postgresDB, userID, modelpostgre.AchievementActionWrite)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "Role ID tidak ditemukan. Silakan login ulang.",
-			},
-		})
-	}
-
-	roleName, err := repositorypostgre.GetRoleName(postgresDB, roleID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "Error mengambil role name. Detail: " + err.Error(),
-			},
-		})
-	}
-
-	if roleName != "Mahasiswa" {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "Akses ditolak. Hanya mahasiswa yang dapat menghapus prestasi.",
-			},
-		})
+		return err
 	}
 
 	mongoID := c.Params("id")
@@ -372,52 +298,42 @@ func DeleteAchievementService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.D
 		})
 	}
 
-	if ref.Status != modelpostgre.AchievementStatusDraft {
+	if !modelpostgre.CanTransitionAchievementStatus(ref.Status, modelpostgre.AchievementStatusDeleted) {
 		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
 			"status": "error",
 			"data": fiber.Map{
-				"message": "Prestasi hanya dapat dihapus jika status adalah draft.",
-			},
-		})
-	}
-
-	studentID, err := repositorypostgre.GetStudentIDByUserID(postgresDB, userID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "Error mengambil data mahasiswa. Detail: " + err.Error(),
+				"message": "Prestasi hanya dapat dihapus jika status adalah draft atau rejected.",
 			},
 		})
 	}
 
-	if ref.StudentID != studentID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "Akses ditolak. Anda hanya dapat menghapus prestasi milik Anda sendiri.",
-			},
-		})
+	if ok, err := authorizeAchievementStudent(c, postgresDB, policy, ref.StudentID, "Akses ditolak. Anda hanya dapat menghapus prestasi milik Anda sendiri."); !ok {
+		return err
 	}
 
-	err = repositorymongo.DeleteAchievement(mongoDB, mongoID)
+	outboxID, err := repositorypostgre.DeleteAchievementReferenceWithOutbox(postgresDB, *ref, userID, achievementOutboxGracePeriod)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Status prestasi sudah berubah. Muat ulang data prestasi.",
+				},
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status": "error",
 			"data": fiber.Map{
-				"message": "Error menghapus prestasi dari database. Detail: " + err.Error(),
+				"message": "Error mengupdate status prestasi menjadi deleted. Detail: " + err.Error(),
 			},
 		})
 	}
 
-	err = repositorypostgre.UpdateAchievementReferenceStatus(postgresDB, ref.ID, modelpostgre.AchievementStatusDeleted, nil)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "Error mengupdate status prestasi menjadi deleted. Detail: " + err.Error(),
-			},
-		})
+	// Reference sudah berstatus deleted; jika soft delete MongoDB gagal, worker outbox akan mengulanginya.
+	if err := repositorymongo.DeleteAchievement(mongoDB, mongoID); err != nil {
+		log.Printf("Failed to delete achievement %s from MongoDB, deferred to outbox %s: %v", mongoID, outboxID, err)
+	} else if err := repositorypostgre.MarkAchievementOutboxStatus(postgresDB, outboxID, modelpostgre.AchievementOutboxStatusCompleted); err != nil {
+		log.Printf("Failed to close achievement outbox %s: %v", outboxID, err)
 	}
 
 	response := modelmongo.DeleteAchievementResponse{
@@ -521,6 +437,9 @@ func UploadFileService(c *fiber.Ctx) error {
 		FileType:   fileType,
 		UploadedAt: time.Now(),
 	}
+	if checksum, err := fileChecksum(filePath); err == nil {
+		attachment.Checksum = checksum
+	}
 
 	response := fiber.Map{
 		"status": "success",
@@ -541,130 +460,32 @@ func GetAchievementsService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.Dat
 		})
 	}
 
-	roleID, ok := c.Locals("role_id").(string)
+	policy, ok, err := resolveAchievementPolicy(c, postgresDB, userID, modelpostgre.AchievementActionRead)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "Role ID tidak ditemukan. Silakan login ulang.",
-			},
-		})
-	}
-
-	roleName, err := repositorypostgre.GetRoleName(postgresDB, roleID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "Error mengambil role name. Detail: " + err.Error(),
-			},
-		})
-	}
-
-	var references []modelpostgre.AchievementReference
-
-	if roleName == "Mahasiswa" {
-		student, err := repositorypostgre.GetStudentByUserID(postgresDB, userID)
-		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-					"message": "Error mengambil data student. Detail: " + err.Error(),
-			},
-		})
+		return err
 	}
 
-		references, err = repositorypostgre.GetAchievementReferenceByStudentID(postgresDB, student.ID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-					"message": "Error mengambil achievement references. Detail: " + err.Error(),
-				},
-			})
-		}
-	} else if roleName == "Dosen Wali" {
-		lecturer, err := repositorypostgre.GetLecturerByUserID(postgresDB, userID)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-					"status": "error",
-					"data": fiber.Map{
-						"message": "Data dosen wali tidak ditemukan. Pastikan user memiliki profil dosen wali.",
-					},
-				})
-			}
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"status": "error",
-				"data": fiber.Map{
-					"message": "Error mengambil data dosen wali. Detail: " + err.Error(),
-			},
-		})
-	}
-
-		references, err = repositorypostgre.GetAchievementReferencesByAdvisorID(postgresDB, lecturer.ID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "Error mengambil achievement references. Detail: " + err.Error(),
-				},
-			})
-		}
-	} else if roleName == "Admin" {
-		references, err = repositorypostgre.GetAllAchievementReferences(postgresDB)
-		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"status": "error",
-				"data": fiber.Map{
-					"message": "Error mengambil achievement references. Detail: " + err.Error(),
-				},
-			})
-		}
-	} else {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "Akses ditolak. Role tidak memiliki akses untuk melihat prestasi.",
-			},
-		})
-	}
-
-	if len(references) == 0 {
-		response := fiber.Map{
-			"status": "success",
-			"data":   []modelmongo.Achievement{},
-		}
-		return c.Status(fiber.StatusOK).JSON(response)
-	}
+	page, limit := helper.ValidatePagination(helper.GetQueryInt(c, "page", 1), helper.GetQueryInt(c, "limit", 10))
 
-	var mongoIDs []string
-	for _, ref := range references {
-		mongoIDs = append(mongoIDs, ref.MongoAchievementID)
+	referenceFilter, achievementFilter, ok, err := parseAchievementListQuery(c)
+	if !ok {
+		return err
 	}
+	scopeAchievementReferenceFilter(policy, &referenceFilter)
 
-	achievements, err := repositorymongo.GetAchievementsByIDs(mongoDB, mongoIDs)
+	items, total, err := listAchievements(postgresDB, mongoDB, referenceFilter, achievementFilter, helper.CalculateOffset(page, limit), limit)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status": "error",
 			"data": fiber.Map{
-				"message": "Error mengambil achievements dari MongoDB. Detail: " + err.Error(),
+				"message": "Error mengambil data prestasi. Detail: " + err.Error(),
 			},
 		})
 	}
 
-	referenceMap := make(map[string]modelpostgre.AchievementReference)
-	for _, ref := range references {
-		referenceMap[ref.MongoAchievementID] = ref
-	}
-
-	var result []fiber.Map
-	for _, achievement := range achievements {
-		ref, exists := referenceMap[achievement.ID.Hex()]
-		if !exists {
-			continue
-		}
-
+	result := []fiber.Map{}
+	for _, item := range items {
+		achievement, ref := item.Achievement, item.Reference
 		result = append(result, fiber.Map{
 			"id":              achievement.ID.Hex(),
 			"studentId":        achievement.StudentID,
@@ -678,18 +499,20 @@ func GetAchievementsService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.Dat
 			"createdAt":        achievement.CreatedAt.Format(time.RFC3339),
 			"updatedAt":        achievement.UpdatedAt.Format(time.RFC3339),
 			"status":           ref.Status,
+			"rejectionNote":    ref.RejectionNote,
 		})
 	}
 
 	response := fiber.Map{
-		"status": "success",
-		"data":   result,
+		"status":     "success",
+		"data":       result,
+		"pagination": modelpostgre.NewPagination(page, limit, total),
 	}
 
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
-func GetAchievementByIDService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.Database) error {
+func SearchAchievementsService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.Database) error {
 	userID, ok := c.Locals("user_id").(string)
 	if !ok {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
@@ -700,126 +523,204 @@ func GetAchievementByIDService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.
 		})
 	}
 
-	roleID, ok := c.Locals("role_id").(string)
-	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+	search := strings.TrimSpace(c.Query("q"))
+	if search == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"status": "error",
 			"data": fiber.Map{
-				"message": "Role ID tidak ditemukan. Silakan login ulang.",
+				"message": "Parameter q wajib diisi.",
 			},
 		})
 	}
 
-	roleName, err := repositorypostgre.GetRoleName(postgresDB, roleID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+	if len(search) > 200 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"status": "error",
 			"data": fiber.Map{
-				"message": "Error mengambil role name. Detail: " + err.Error(),
+				"message": "Parameter q maksimal 200 karakter.",
 			},
 		})
 	}
 
-	mongoID := c.Params("id")
-	if mongoID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "ID prestasi wajib diisi.",
-			},
-		})
+	policy, ok, err := resolveAchievementPolicy(c, postgresDB, userID, modelpostgre.AchievementActionRead)
+	if !ok {
+		return err
 	}
 
-	ref, err := repositorypostgre.GetAchievementReferenceByMongoID(postgresDB, mongoID)
+	page, limit := helper.ValidatePagination(helper.GetQueryInt(c, "page", 1), helper.GetQueryInt(c, "limit", 10))
+
+	referenceFilter, achievementFilter, ok, err := parseAchievementListQuery(c)
+	if !ok {
+		return err
+	}
+	scopeAchievementReferenceFilter(policy, &referenceFilter)
+
+	items, total, err := searchAchievements(postgresDB, mongoDB, search, referenceFilter, achievementFilter, helper.CalculateOffset(page, limit), limit)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status": "error",
-				"data": fiber.Map{
-					"message": "Prestasi tidak ditemukan.",
-				},
-			})
-		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status": "error",
 			"data": fiber.Map{
-				"message": "Error mengambil data prestasi dari database. Detail: " + err.Error(),
+				"message": "Error mencari data prestasi. Detail: " + err.Error(),
 			},
 		})
 	}
 
-	if roleName == "Mahasiswa" {
-	studentID, err := repositorypostgre.GetStudentIDByUserID(postgresDB, userID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+	terms := helper.SearchTerms(search)
+
+	result := []fiber.Map{}
+	for _, item := range items {
+		achievement, ref := item.Achievement, item.Reference
+
+		highlights := fiber.Map{}
+		if title := helper.HighlightText(achievement.Title, terms, 0); title != "" {
+			highlights["title"] = title
+		}
+		if description := helper.HighlightText(achievement.Description, terms, 160); description != "" {
+			highlights["description"] = description
+		}
+
+		result = append(result, fiber.Map{
+			"id":              achievement.ID.Hex(),
+			"studentId":       achievement.StudentID,
+			"achievementType": achievement.AchievementType,
+			"title":           achievement.Title,
+			"description":     achievement.Description,
+			"details":         achievement.Details,
+			"attachments":     achievement.Attachments,
+			"tags":            achievement.Tags,
+			"points":          achievement.Points,
+			"createdAt":       achievement.CreatedAt.Format(time.RFC3339),
+			"updatedAt":       achievement.UpdatedAt.Format(time.RFC3339),
+			"status":          ref.Status,
+			"rejectionNote":   ref.RejectionNote,
+			"score":           item.Score,
+			"highlights":      highlights,
+		})
+	}
+
+	response := fiber.Map{
+		"status":     "success",
+		"data":       result,
+		"pagination": modelpostgre.NewPagination(page, limit, total),
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// parseAchievementListQuery membaca query parameter filter dan sort daftar prestasi.
+// Jika ok bernilai false, response error sudah ditulis.
+func parseAchievementListQuery(c *fiber.Ctx) (modelpostgre.AchievementReferenceFilter, modelmongo.AchievementFilter, bool, error) {
+	referenceFilter := modelpostgre.AchievementReferenceFilter{
+		StudentID: c.Query("studentId"),
+		Status:    c.Query("status"),
+	}
+	achievementFilter := modelmongo.AchievementFilter{
+		AchievementType: c.Query("type"),
+	}
+
+	badRequest := func(message string) (modelpostgre.AchievementReferenceFilter, modelmongo.AchievementFilter, bool, error) {
+		return referenceFilter, achievementFilter, false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"status": "error",
 			"data": fiber.Map{
-				"message": "Error mengambil data mahasiswa. Detail: " + err.Error(),
+				"message": message,
 			},
 		})
 	}
 
-	if ref.StudentID != studentID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "Akses ditolak. Anda hanya dapat melihat prestasi milik Anda sendiri.",
-				},
-			})
+	if referenceFilter.StudentID != "" && !helper.IsValidUUID(referenceFilter.StudentID) {
+		return badRequest("Student ID tidak valid.")
+	}
+
+	validStatuses := map[string]bool{
+		modelpostgre.AchievementStatusDraft:     true,
+		modelpostgre.AchievementStatusSubmitted: true,
+		modelpostgre.AchievementStatusVerified:  true,
+		modelpostgre.AchievementStatusRejected:  true,
+	}
+
+	if referenceFilter.Status != "" && !validStatuses[referenceFilter.Status] {
+		return badRequest("Status tidak valid. Gunakan: draft, submitted, verified, atau rejected.")
+	}
+
+	if achievementFilter.AchievementType != "" && !modelmongo.IsValidAchievementType(achievementFilter.AchievementType) {
+		return badRequest("Achievement type tidak valid. Gunakan: academic, competition, organization, publication, certification, atau other.")
+	}
+
+	if tags := c.Query("tags"); tags != "" {
+		for _, tag := range strings.Split(tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				achievementFilter.Tags = append(achievementFilter.Tags, tag)
+			}
+		}
+	}
+
+	for _, param := range []string{"minPoints", "maxPoints"} {
+		value := c.Query(param)
+		if value == "" {
+			continue
 		}
-	} else if roleName == "Dosen Wali" {
-		lecturer, err := repositorypostgre.GetLecturerByUserID(postgresDB, userID)
+		points, err := strconv.Atoi(value)
 		if err != nil {
-			if err == sql.ErrNoRows {
-				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-					"status": "error",
-					"data": fiber.Map{
-						"message": "Data dosen wali tidak ditemukan. Pastikan user memiliki profil dosen wali.",
-					},
-				})
-			}
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"status": "error",
-				"data": fiber.Map{
-					"message": "Error mengambil data dosen wali. Detail: " + err.Error(),
-				},
-			})
+			return badRequest("Parameter " + param + " harus berupa angka.")
+		}
+		if param == "minPoints" {
+			achievementFilter.MinPoints = &points
+		} else {
+			achievementFilter.MaxPoints = &points
+		}
+	}
+
+	if achievementFilter.MinPoints != nil && achievementFilter.MaxPoints != nil && *achievementFilter.MinPoints > *achievementFilter.MaxPoints {
+		return badRequest("Parameter minPoints tidak boleh lebih besar dari maxPoints.")
+	}
+
+	if value := c.Query("dateFrom"); value != "" {
+		dateFrom, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return badRequest("Parameter dateFrom harus berformat YYYY-MM-DD.")
 		}
+		achievementFilter.DateFrom = &dateFrom
+	}
 
-		var student modelpostgre.Student
-		query := `SELECT id, user_id, student_id, program_study, academic_year, advisor_id, created_at FROM students WHERE id = $1`
-		err = postgresDB.QueryRow(query, ref.StudentID).Scan(
-			&student.ID, &student.UserID, &student.StudentID,
-			&student.ProgramStudy, &student.AcademicYear, &student.AdvisorID,
-			&student.CreatedAt,
-		)
+	if value := c.Query("dateTo"); value != "" {
+		dateTo, err := time.Parse("2006-01-02", value)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"status": "error",
-				"data": fiber.Map{
-					"message": "Error mengambil data student. Detail: " + err.Error(),
-				},
-			})
+			return badRequest("Parameter dateTo harus berformat YYYY-MM-DD.")
 		}
+		// dateTo inklusif, sehingga batas atas adalah awal hari berikutnya.
+		dateTo = dateTo.AddDate(0, 0, 1)
+		achievementFilter.DateTo = &dateTo
+	}
 
-		if student.AdvisorID != lecturer.ID {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-				"status": "error",
-				"data": fiber.Map{
-					"message": "Akses ditolak. Anda hanya dapat melihat prestasi mahasiswa bimbingan Anda.",
-				},
-			})
+	sortFields := map[string]bool{
+		"createdAt": true,
+		"updatedAt": true,
+		"points":    true,
+		"title":     true,
+	}
+
+	if sort := c.Query("sort"); sort != "" {
+		achievementFilter.SortOrder = 1
+		if strings.HasPrefix(sort, "-") {
+			achievementFilter.SortOrder = -1
+			sort = strings.TrimPrefix(sort, "-")
 		}
-	} else if roleName != "Admin" {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"status": "error",
-			"data": fiber.Map{
-				"message": "Akses ditolak. Role tidak memiliki akses untuk melihat prestasi.",
-			},
-		})
+		if !sortFields[sort] {
+			return badRequest("Parameter sort tidak valid. Gunakan: createdAt, updatedAt, points, atau title (awali dengan '-' untuk urutan menurun).")
+		}
+		achievementFilter.SortField = sort
+	}
+
+	return referenceFilter, achievementFilter, true, nil
+}
+
+func GetAchievementByIDService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.Database) error {
+	ref, ok, err := getReadableAchievementReference(c, postgresDB)
+	if !ok {
+		return err
 	}
 
-	achievement, err := repositorymongo.GetAchievementByID(mongoDB, mongoID)
+	achievement, err := repositorymongo.GetAchievementByID(mongoDB, ref.MongoAchievementID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status": "error",
@@ -842,6 +743,22 @@ func GetAchievementByIDService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.
 		"createdAt":       achievement.CreatedAt.Format(time.RFC3339),
 		"updatedAt":       achievement.UpdatedAt.Format(time.RFC3339),
 		"status":          ref.Status,
+		"rejectionNote":   ref.RejectionNote,
+	}
+
+	// Prestasi verified membawa kode verifikasi publik, misalnya untuk dicetak pada sertifikat.
+	if ref.Status == modelpostgre.AchievementStatusVerified {
+		code, err := utilspostgre.GenerateVerificationCode(modelpostgre.VerificationTypeAchievement, ref.ID)
+		var url string
+		if err == nil {
+			url, err = utilspostgre.VerificationURL(code)
+		}
+		if err != nil {
+			log.Printf("Failed to create verification code for achievement %s: %v", ref.ID, err)
+		} else {
+			result["verificationCode"] = code
+			result["verificationUrl"] = url
+		}
 	}
 
 	responseData := fiber.Map{
@@ -852,10 +769,43 @@ func GetAchievementByIDService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.
 	return c.Status(fiber.StatusOK).JSON(responseData)
 }
 
-func UpdateAchievementService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.Database) error {
+func GetAchievementHistoryService(c *fiber.Ctx, postgresDB *sql.DB) error {
+	ref, ok, err := getReadableAchievementReference(c, postgresDB)
+	if !ok {
+		return err
+	}
+
+	history, err := repositorypostgre.GetAchievementStatusHistory(postgresDB, ref.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil riwayat status prestasi. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := modelpostgre.GetAchievementStatusHistoryResponse{
+		Status: "success",
+		Data:   history,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// getReadableAchievementReference mengambil reference prestasi dari parameter :id yang boleh
+// dilihat oleh user. Jika ok bernilai false, response error sudah ditulis.
+func getReadableAchievementReference(c *fiber.Ctx, postgresDB *sql.DB) (*modelpostgre.AchievementReference, bool, error) {
+	ref, _, ok, err := getReadableAchievementReferenceWithPolicy(c, postgresDB)
+	return ref, ok, err
+}
+
+// getReadableAchievementReferenceWithPolicy sama seperti getReadableAchievementReference, tetapi
+// juga mengembalikan policy baca user untuk pemeriksaan tambahan.
+func getReadableAchievementReferenceWithPolicy(c *fiber.Ctx, postgresDB *sql.DB) (*modelpostgre.AchievementReference, *achievementPolicy, bool, error) {
 	userID, ok := c.Locals("user_id").(string)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+		return nil, nil, false, c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 			"status": "error",
 			"data": fiber.Map{
 				"message": "User ID tidak ditemukan. Silakan login ulang.",
@@ -863,35 +813,62 @@ func UpdateAchievementService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.D
 		})
 	}
 
-	roleID, ok := c.Locals("role_id").(string)
+	policy, ok, err := resolveAchievementPolicy(c, postgresDB, userID, modelpostgre.AchievementActionRead)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+		return nil, nil, false, err
+	}
+
+	mongoID := c.Params("id")
+	if mongoID == "" {
+		return nil, nil, false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"status": "error",
 			"data": fiber.Map{
-				"message": "Role ID tidak ditemukan. Silakan login ulang.",
+				"message": "ID prestasi wajib diisi.",
 			},
 		})
 	}
 
-	roleName, err := repositorypostgre.GetRoleName(postgresDB, roleID)
+	ref, err := repositorypostgre.GetAchievementReferenceByMongoID(postgresDB, mongoID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		if err == sql.ErrNoRows {
+			return nil, nil, false, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Prestasi tidak ditemukan.",
+				},
+			})
+		}
+		return nil, nil, false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status": "error",
 			"data": fiber.Map{
-				"message": "Error mengambil role name. Detail: " + err.Error(),
+				"message": "Error mengambil data prestasi dari database. Detail: " + err.Error(),
 			},
 		})
 	}
 
-	if roleName != "Mahasiswa" {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+	if ok, err := authorizeAchievementReader(c, postgresDB, policy, ref, "Akses ditolak. Anda tidak memiliki akses untuk melihat prestasi ini."); !ok {
+		return nil, nil, false, err
+	}
+
+	return ref, policy, true, nil
+}
+
+func UpdateAchievementService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.Database) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 			"status": "error",
 			"data": fiber.Map{
-				"message": "Akses ditolak. Hanya mahasiswa yang dapat mengupdate prestasi.",
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
 			},
 		})
 	}
 
+	policy, ok, err := resolveAchievementPolicy(c, postgresDB, userID, modelpostgre.AchievementActionWrite)
+	if !ok {
+		return err
+	}
+
 	mongoID := c.Params("id")
 	if mongoID == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -920,66 +897,86 @@ func UpdateAchievementService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.D
 		})
 	}
 
-	if ref.Status != modelpostgre.AchievementStatusDraft {
+	if !modelpostgre.IsAchievementEditable(ref.Status) {
 		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
 			"status": "error",
 			"data": fiber.Map{
-				"message": "Prestasi hanya dapat diupdate jika status adalah draft.",
+				"message": "Prestasi hanya dapat diupdate jika status adalah draft atau rejected.",
 			},
 		})
 	}
 
-	studentID, err := repositorypostgre.GetStudentIDByUserID(postgresDB, userID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+	if ok, err := authorizeAchievementStudent(c, postgresDB, policy, ref.StudentID, "Akses ditolak. Anda hanya dapat mengupdate prestasi milik Anda sendiri."); !ok {
+		return err
+	}
+
+	var req modelmongo.UpdateAchievementRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"status": "error",
 			"data": fiber.Map{
-				"message": "Error mengambil data mahasiswa. Detail: " + err.Error(),
+				"message": "Format request body tidak valid. Pastikan JSON format benar. Detail: " + err.Error(),
 			},
 		})
 	}
 
-	if ref.StudentID != studentID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+	existingAchievement, err := repositorymongo.GetAchievementByID(mongoDB, mongoID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status": "error",
 			"data": fiber.Map{
-				"message": "Akses ditolak. Anda hanya dapat mengupdate prestasi milik Anda sendiri.",
+				"message": "Error mengambil achievement dari database. Detail: " + err.Error(),
 			},
 		})
 	}
 
-	var req modelmongo.UpdateAchievementRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+	achievementType := existingAchievement.AchievementType
+	details := existingAchievement.Details
+	if req.AchievementType != "" {
+		achievementType = req.AchievementType
+	}
+	if req.Details != nil {
+		details = *req.Details
+	}
+
+	fieldErrors := []modelmongo.FieldError{}
+	if !modelmongo.IsValidAchievementType(achievementType) {
+		fieldErrors = append(fieldErrors, modelmongo.FieldError{Field: "achievementType", Message: "Achievement type tidak valid. Gunakan: academic, competition, organization, publication, certification, atau other."})
+	}
+	fieldErrors = append(fieldErrors, modelmongo.ValidateAchievementDetails(achievementType, details)...)
+
+	if len(fieldErrors) > 0 {
+		return helper.FieldErrorsResponse(c, "Validasi prestasi gagal.", fieldErrors)
+	}
+
+	points, pointRubricVersion, err := calculateAchievementPoints(postgresDB, achievementType, details)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status": "error",
 			"data": fiber.Map{
-				"message": "Format request body tidak valid. Pastikan JSON format benar. Detail: " + err.Error(),
+				"message": "Error menghitung poin prestasi. Detail: " + err.Error(),
 			},
 		})
 	}
+	req.Points = &points
+	req.PointRubricVersion = &pointRubricVersion
+	fillAttachmentChecksums(req.Attachments)
 
-	if req.AchievementType != "" {
-		validTypes := map[string]bool{
-			"academic":      true,
-			"competition":   true,
-			"organization":  true,
-			"publication":   true,
-			"certification": true,
-			"other":         true,
-		}
-
-		if !validTypes[req.AchievementType] {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"status": "error",
-				"data": fiber.Map{
-					"message": "Achievement type tidak valid. Gunakan: academic, competition, organization, publication, certification, atau other.",
-				},
-			})
-		}
+	revision, err := recordAchievementRevision(mongoDB, *existingAchievement, modelmongo.ApplyAchievementUpdate(*existingAchievement, req), ref.Status, userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error menyimpan revisi prestasi. Detail: " + err.Error(),
+			},
+		})
 	}
 
 	updatedAchievement, err := repositorymongo.UpdateAchievement(mongoDB, mongoID, req)
 	if err != nil {
+		if deleteErr := repositorymongo.DeleteAchievementRevision(mongoDB, revision.ID); deleteErr != nil {
+			log.Printf("Failed to remove revision %d of achievement %s: %v", revision.Revision, mongoID, deleteErr)
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status": "error",
 			"data": fiber.Map{
@@ -988,6 +985,27 @@ func UpdateAchievementService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.D
 		})
 	}
 
+	if ref.Status != modelpostgre.AchievementStatusDraft {
+		err = repositorypostgre.UpdateAchievementReferenceStatus(postgresDB, ref.ID, modelpostgre.AchievementStatusDraft, nil, userID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+					"status": "error",
+					"data": fiber.Map{
+						"message": "Status prestasi sudah berubah. Muat ulang data prestasi.",
+					},
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Error mengembalikan status prestasi menjadi draft. Detail: " + err.Error(),
+				},
+			})
+		}
+		ref.Status = modelpostgre.AchievementStatusDraft
+	}
+
 	result := fiber.Map{
 		"id":              updatedAchievement.ID.Hex(),
 		"studentId":       updatedAchievement.StudentID,
@@ -1001,6 +1019,7 @@ func UpdateAchievementService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.D
 		"createdAt":       updatedAchievement.CreatedAt.Format(time.RFC3339),
 		"updatedAt":       updatedAchievement.UpdatedAt.Format(time.RFC3339),
 		"status":          ref.Status,
+		"rejectionNote":   ref.RejectionNote,
 	}
 
 	responseData := fiber.Map{
@@ -1011,6 +1030,222 @@ func UpdateAchievementService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.D
 	return c.Status(fiber.StatusOK).JSON(responseData)
 }
 
+func VerifyAchievementService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.Database) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			},
+		})
+	}
+
+	ref, ok, err := getAchievementForVerifier(c, postgresDB, userID, modelpostgre.AchievementStatusVerified)
+	if !ok {
+		return err
+	}
+
+	// Poin dihitung ulang dengan rubrik aktif saat verifikasi agar prestasi yang dibuat
+	// dengan rubrik lama tetap dinilai dengan aturan terbaru.
+	achievement, err := repositorymongo.GetAchievementByID(mongoDB, ref.MongoAchievementID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil achievement dari database. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	points, pointRubricVersion, err := calculateAchievementPoints(postgresDB, achievement.AchievementType, achievement.Details)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error menghitung poin prestasi. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	participantPoints, err := calculateParticipantPoints(postgresDB, ref.ID, achievement.AchievementType, points)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error menghitung pembagian poin peserta prestasi. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	outboxID, err := repositorypostgre.VerifyAchievementReference(postgresDB, *ref, userID, participantPoints, achievementOutboxGracePeriod)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Status prestasi sudah berubah. Muat ulang data prestasi.",
+				},
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error memverifikasi prestasi. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	// Poin baru disimpan ke MongoDB setelah verifikasi di-commit; jika gagal, worker outbox
+	// menghitung ulang dan menyimpannya.
+	if err := repositorymongo.UpdateAchievementPoints(mongoDB, ref.MongoAchievementID, points, pointRubricVersion); err != nil {
+		log.Printf("Failed to update points of achievement %s, deferred to outbox %s: %v", ref.MongoAchievementID, outboxID, err)
+	} else if err := repositorypostgre.MarkAchievementOutboxStatus(postgresDB, outboxID, modelpostgre.AchievementOutboxStatusCompleted); err != nil {
+		log.Printf("Failed to close achievement outbox %s: %v", outboxID, err)
+	}
+
+	updatedRef, err := repositorypostgre.GetAchievementReferenceByID(postgresDB, ref.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil data prestasi yang diupdate. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := modelpostgre.UpdateAchievementReferenceResponse{
+		Status: "success",
+		Data:   *updatedRef,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func RejectAchievementService(c *fiber.Ctx, postgresDB *sql.DB) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			},
+		})
+	}
+
+	var req modelpostgre.RejectAchievementRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Format request body tidak valid. Pastikan JSON format benar. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	req.RejectionNote = strings.TrimSpace(req.RejectionNote)
+	if req.RejectionNote == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Rejection note wajib diisi.",
+			},
+		})
+	}
+
+	ref, ok, err := getAchievementForVerifier(c, postgresDB, userID, modelpostgre.AchievementStatusRejected)
+	if !ok {
+		return err
+	}
+
+	err = repositorypostgre.RejectAchievementReference(postgresDB, ref.ID, userID, req.RejectionNote)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Status prestasi sudah berubah. Muat ulang data prestasi.",
+				},
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error menolak prestasi. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	updatedRef, err := repositorypostgre.GetAchievementReferenceByID(postgresDB, ref.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil data prestasi yang diupdate. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := modelpostgre.UpdateAchievementReferenceResponse{
+		Status: "success",
+		Data:   *updatedRef,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// getAchievementForVerifier mengambil reference prestasi yang boleh dipindahkan ke targetStatus
+// oleh verifikator. Jika ok bernilai false, response error sudah ditulis.
+func getAchievementForVerifier(c *fiber.Ctx, postgresDB *sql.DB, userID string, targetStatus string) (*modelpostgre.AchievementReference, bool, error) {
+	policy, ok, err := resolveAchievementPolicy(c, postgresDB, userID, modelpostgre.AchievementActionVerify)
+	if !ok {
+		return nil, false, err
+	}
+
+	mongoID := c.Params("id")
+	if mongoID == "" {
+		return nil, false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "ID prestasi wajib diisi.",
+			},
+		})
+	}
+
+	ref, err := repositorypostgre.GetAchievementReferenceByMongoID(postgresDB, mongoID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Prestasi tidak ditemukan.",
+				},
+			})
+		}
+		return nil, false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil data prestasi dari database. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	if !modelpostgre.CanTransitionAchievementStatus(ref.Status, targetStatus) {
+		return nil, false, c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Prestasi hanya dapat diverifikasi atau ditolak jika status adalah submitted.",
+			},
+		})
+	}
+
+	if ok, err := authorizeAchievementStudent(c, postgresDB, policy, ref.StudentID, "Akses ditolak. Anda hanya dapat memverifikasi prestasi mahasiswa bimbingan Anda."); !ok {
+		return nil, false, err
+	}
+
+	return ref, true, nil
+}
+
 func GetAchievementStatsService(c *fiber.Ctx, postgresDB *sql.DB) error {
 	total, verified, err := repositorypostgre.GetAchievementStats(postgresDB)
 	if err != nil {
@@ -0,0 +1,307 @@
+package service
+
+import (
+	"database/sql"
+	model "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repository "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/helper"
+	utilspostgre "sistem-pelaporan-prestasi-mahasiswa/utils/postgre"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func GetLecturersService(c *fiber.Ctx, db *sql.DB) error {
+	page, limit := helper.ValidatePagination(helper.GetQueryInt(c, "page", 1), helper.GetQueryInt(c, "limit", 10))
+
+	filter := model.LecturerFilter{
+		Search: helper.SanitizeSearch(c.Query("search")),
+		Limit:  limit,
+		Offset: helper.CalculateOffset(page, limit),
+	}
+
+	lecturers, total, err := repository.GetAllLecturers(db, filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil data dosen dari database. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.GetAllLecturersResponse{
+		Status:     "success",
+		Data:       lecturers,
+		Pagination: model.NewPagination(page, limit, total),
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func GetLecturerByIDService(c *fiber.Ctx, db *sql.DB) error {
+	id := c.Params("id")
+	if !helper.IsValidUUID(id) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "ID dosen tidak valid.",
+			},
+		})
+	}
+
+	lecturer, err := repository.GetLecturerByID(db, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Data dosen tidak ditemukan.",
+				},
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil data dosen dari database. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.GetLecturerByIDResponse{
+		Status: "success",
+		Data:   *lecturer,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func CreateLecturerService(c *fiber.Ctx, db *sql.DB) error {
+	var req model.CreateLecturerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Format request body tidak valid. Pastikan JSON format benar. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	req.LecturerID = strings.TrimSpace(req.LecturerID)
+	if req.UserID == "" || req.LecturerID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "User ID dan lecturer_id (NIP) wajib diisi.",
+			},
+		})
+	}
+
+	if ok, err := validateProfileUser(c, db, req.UserID, model.AchievementScopePermission(model.AchievementActionRead, model.AchievementScopeAdvisees)); !ok {
+		return err
+	}
+
+	lecturer, err := repository.CreateLecturer(db, req)
+	if err != nil {
+		if constraint, ok := helper.IsUniqueViolation(err); ok {
+			return helper.ConflictResponse(c, lecturerConflictMessage(constraint))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error menyimpan data dosen ke database. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.CreateLecturerResponse{
+		Status: "success",
+		Data:   *lecturer,
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+func UpdateLecturerService(c *fiber.Ctx, db *sql.DB) error {
+	id := c.Params("id")
+	if !helper.IsValidUUID(id) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "ID dosen tidak valid.",
+			},
+		})
+	}
+
+	var req model.UpdateLecturerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Format request body tidak valid. Pastikan JSON format benar. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	req.LecturerID = strings.TrimSpace(req.LecturerID)
+	if req.LecturerID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Lecturer ID (NIP) wajib diisi.",
+			},
+		})
+	}
+
+	lecturer, err := repository.UpdateLecturer(db, id, req)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Data dosen tidak ditemukan.",
+				},
+			})
+		}
+		if constraint, ok := helper.IsUniqueViolation(err); ok {
+			return helper.ConflictResponse(c, lecturerConflictMessage(constraint))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengupdate data dosen. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.UpdateLecturerResponse{
+		Status: "success",
+		Data:   *lecturer,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func DeleteLecturerService(c *fiber.Ctx, db *sql.DB) error {
+	id := c.Params("id")
+	if !helper.IsValidUUID(id) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "ID dosen tidak valid.",
+			},
+		})
+	}
+
+	if err := repository.DeleteLecturer(db, id); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Data dosen tidak ditemukan.",
+				},
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error menghapus data dosen. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.DeleteLecturerResponse{
+		Status: "success",
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func GetLecturerAdviseesService(c *fiber.Ctx, db *sql.DB) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			},
+		})
+	}
+
+	id := c.Params("id")
+	if !helper.IsValidUUID(id) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "ID dosen tidak valid.",
+			},
+		})
+	}
+
+	lecturer, err := repository.GetLecturerByID(db, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Data dosen tidak ditemukan.",
+				},
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil data dosen dari database. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	if lecturer.UserID != userID {
+		canManage, err := utilspostgre.CheckUserPermission(db, userID, "user:manage")
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Gagal memeriksa permission: " + err.Error(),
+				},
+			})
+		}
+
+		if !canManage {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Akses ditolak. Anda hanya dapat melihat mahasiswa bimbingan Anda sendiri.",
+				},
+			})
+		}
+	}
+
+	students, err := repository.GetStudentsByAdvisorID(db, lecturer.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil data mahasiswa bimbingan. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.GetLecturerAdviseesResponse{
+		Status: "success",
+		Data:   students,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func lecturerConflictMessage(constraint string) string {
+	switch constraint {
+	case "lecturers_lecturer_id_key":
+		return "NIP sudah terdaftar."
+	case "lecturers_user_id_key":
+		return "User sudah memiliki profil dosen."
+	default:
+		return "Data dosen sudah ada."
+	}
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"database/sql"
+	model "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repository "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/helper"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func GetPermissionsService(c *fiber.Ctx, db *sql.DB) error {
+	permissions, err := repository.GetAllPermissions(db)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil data permission dari database. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.GetAllPermissionsResponse{
+		Status: "success",
+		Data:   permissions,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func CreatePermissionService(c *fiber.Ctx, db *sql.DB) error {
+	var req model.CreatePermissionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Format request body tidak valid. Pastikan JSON format benar. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	req.Resource = strings.TrimSpace(req.Resource)
+	req.Action = strings.TrimSpace(req.Action)
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		req.Name = req.Resource + ":" + req.Action
+	}
+
+	if req.Resource == "" || req.Action == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Resource dan action wajib diisi.",
+			},
+		})
+	}
+
+	permission, err := repository.CreatePermission(db, req)
+	if err != nil {
+		if _, ok := helper.IsUniqueViolation(err); ok {
+			return helper.ConflictResponse(c, "Nama permission sudah digunakan.")
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error menyimpan permission ke database. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.CreatePermissionResponse{
+		Status: "success",
+		Data:   *permission,
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+func UpdatePermissionService(c *fiber.Ctx, db *sql.DB) error {
+	permission, ok, err := getPermissionFromParams(c, db)
+	if !ok {
+		return err
+	}
+
+	var req model.UpdatePermissionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Format request body tidak valid. Pastikan JSON format benar. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	req.Resource = strings.TrimSpace(req.Resource)
+	req.Action = strings.TrimSpace(req.Action)
+	if req.Name == "" || req.Resource == "" || req.Action == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Name, resource, dan action wajib diisi.",
+			},
+		})
+	}
+
+	if permission.Name == model.PermissionUserManage && req.Name != permission.Name {
+		return helper.ConflictResponse(c, "Permission '"+model.PermissionUserManage+"' tidak dapat diganti namanya.")
+	}
+
+	updatedPermission, err := repository.UpdatePermission(db, permission.ID, req)
+	if err != nil {
+		if _, ok := helper.IsUniqueViolation(err); ok {
+			return helper.ConflictResponse(c, "Nama permission sudah digunakan.")
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengupdate permission. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.UpdatePermissionResponse{
+		Status: "success",
+		Data:   *updatedPermission,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func DeletePermissionService(c *fiber.Ctx, db *sql.DB) error {
+	permission, ok, err := getPermissionFromParams(c, db)
+	if !ok {
+		return err
+	}
+
+	if permission.Name == model.PermissionUserManage {
+		return helper.ConflictResponse(c, "Permission '"+model.PermissionUserManage+"' tidak dapat dihapus.")
+	}
+
+	if err := repository.DeletePermission(db, permission.ID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error menghapus permission. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.DeletePermissionResponse{
+		Status: "success",
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func getPermissionFromParams(c *fiber.Ctx, db *sql.DB) (*model.Permission, bool, error) {
+	id := c.Params("id")
+	if !helper.IsValidUUID(id) {
+		return nil, false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "ID permission tidak valid.",
+			},
+		})
+	}
+
+	permission, err := repository.GetPermissionByID(db, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Permission tidak ditemukan.",
+				},
+			})
+		}
+		return nil, false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil data permission. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	return permission, true, nil
+}
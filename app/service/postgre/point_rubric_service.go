@@ -0,0 +1,347 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/helper"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func GetPointRubricsService(c *fiber.Ctx, postgresDB *sql.DB) error {
+	rubrics, err := repositorypostgre.GetAllPointRubrics(postgresDB)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil rubrik poin dari database. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := modelpostgre.GetAllPointRubricsResponse{
+		Status: "success",
+		Data:   rubrics,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func GetActivePointRubricService(c *fiber.Ctx, postgresDB *sql.DB) error {
+	return getPointRubricByStatus(c, postgresDB, modelpostgre.PointRubricStatusPublished, "Belum ada rubrik poin yang diterbitkan.")
+}
+
+func GetDraftPointRubricService(c *fiber.Ctx, postgresDB *sql.DB) error {
+	return getPointRubricByStatus(c, postgresDB, modelpostgre.PointRubricStatusDraft, "Draft rubrik poin tidak ditemukan.")
+}
+
+func SaveDraftPointRubricService(c *fiber.Ctx, postgresDB *sql.DB) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			},
+		})
+	}
+
+	var req modelpostgre.SavePointRubricRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Format request body tidak valid. Pastikan JSON format benar. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	fieldErrors := validatePointRules(req.Rules)
+	fieldErrors = append(fieldErrors, validateTeamSplitRules(req.TeamSplitRules)...)
+	if len(fieldErrors) > 0 {
+		return helper.FieldErrorsResponse(c, "Validasi rubrik poin gagal.", fieldErrors)
+	}
+
+	rubric, err := repositorypostgre.SaveDraftPointRubric(postgresDB, req.Rules, req.TeamSplitRules, userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error menyimpan draft rubrik poin. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := modelpostgre.GetPointRubricResponse{
+		Status: "success",
+		Data:   *rubric,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func DeleteDraftPointRubricService(c *fiber.Ctx, postgresDB *sql.DB) error {
+	err := repositorypostgre.DeleteDraftPointRubric(postgresDB)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Draft rubrik poin tidak ditemukan.",
+				},
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error menghapus draft rubrik poin. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := modelpostgre.DeletePointRubricResponse{
+		Status: "success",
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func PreviewDraftPointRubricService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.Database) error {
+	draft, err := repositorypostgre.GetPointRubricByStatus(postgresDB, modelpostgre.PointRubricStatusDraft)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Draft rubrik poin tidak ditemukan.",
+				},
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil draft rubrik poin. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	references, err := repositorypostgre.GetAllAchievementReferences(postgresDB)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil achievement references. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	referenceMap := make(map[string]modelpostgre.AchievementReference)
+	var mongoIDs []string
+	for _, ref := range references {
+		mongoIDs = append(mongoIDs, ref.MongoAchievementID)
+		referenceMap[ref.MongoAchievementID] = ref
+	}
+
+	achievements, err := repositorymongo.GetAchievementsByIDs(mongoDB, mongoIDs)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil achievements dari MongoDB. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	preview := modelpostgre.PointRubricPreview{
+		DraftVersion: draft.Version,
+		Items:        []modelpostgre.PointRubricPreviewItem{},
+	}
+	var changed []modelpostgre.PointRubricPreviewItem
+	for _, achievement := range achievements {
+		ref, exists := referenceMap[achievement.ID.Hex()]
+		if !exists {
+			continue
+		}
+
+		preview.Evaluated++
+		newPoints := draft.CalculatePoints(pointCriteriaFromAchievement(achievement.AchievementType, achievement.Details))
+		if newPoints == achievement.Points {
+			continue
+		}
+
+		changed = append(changed, modelpostgre.PointRubricPreviewItem{
+			AchievementID: achievement.ID.Hex(),
+			StudentID:     achievement.StudentID,
+			Title:         achievement.Title,
+			Status:        ref.Status,
+			CurrentPoints: achievement.Points,
+			NewPoints:     newPoints,
+		})
+	}
+	preview.Changed = len(changed)
+
+	page, limit := helper.ValidatePagination(helper.GetQueryInt(c, "page", 1), helper.GetQueryInt(c, "limit", 10))
+	offset := helper.CalculateOffset(page, limit)
+	if offset < len(changed) {
+		end := offset + limit
+		if end > len(changed) {
+			end = len(changed)
+		}
+		preview.Items = changed[offset:end]
+	}
+
+	response := modelpostgre.PreviewPointRubricResponse{
+		Status:     "success",
+		Data:       preview,
+		Pagination: modelpostgre.NewPagination(page, limit, len(changed)),
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func PublishDraftPointRubricService(c *fiber.Ctx, postgresDB *sql.DB) error {
+	rubric, err := repositorypostgre.PublishDraftPointRubric(postgresDB)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Draft rubrik poin tidak ditemukan.",
+				},
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error menerbitkan rubrik poin. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := modelpostgre.GetPointRubricResponse{
+		Status: "success",
+		Data:   *rubric,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func getPointRubricByStatus(c *fiber.Ctx, postgresDB *sql.DB, status string, notFoundMessage string) error {
+	rubric, err := repositorypostgre.GetPointRubricByStatus(postgresDB, status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": notFoundMessage,
+				},
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil rubrik poin. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := modelpostgre.GetPointRubricResponse{
+		Status: "success",
+		Data:   *rubric,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func validatePointRules(rules []modelpostgre.PointRule) []modelmongo.FieldError {
+	fieldErrors := []modelmongo.FieldError{}
+	if len(rules) == 0 {
+		return append(fieldErrors, modelmongo.FieldError{Field: "rules", Message: "Minimal satu rule wajib diisi."})
+	}
+
+	for i, rule := range rules {
+		field := fmt.Sprintf("rules[%d]", i)
+		if !modelmongo.IsValidAchievementType(rule.AchievementType) {
+			fieldErrors = append(fieldErrors, modelmongo.FieldError{Field: field + ".achievement_type", Message: "Achievement type tidak valid. Gunakan: academic, competition, organization, publication, certification, atau other."})
+		}
+		if rule.CompetitionLevel != "" && !modelmongo.IsValidCompetitionLevel(rule.CompetitionLevel) {
+			fieldErrors = append(fieldErrors, modelmongo.FieldError{Field: field + ".competition_level", Message: "Tingkat kompetisi tidak valid. Gunakan: international, national, regional, atau local."})
+		}
+		if rule.PublicationType != "" && !modelmongo.IsValidPublicationType(rule.PublicationType) {
+			fieldErrors = append(fieldErrors, modelmongo.FieldError{Field: field + ".publication_type", Message: "Tipe publikasi tidak valid. Gunakan: journal, conference, atau book."})
+		}
+		if rule.Rank != nil && *rule.Rank < 1 {
+			fieldErrors = append(fieldErrors, modelmongo.FieldError{Field: field + ".rank", Message: "Peringkat minimal 1."})
+		}
+		if rule.Points < 0 {
+			fieldErrors = append(fieldErrors, modelmongo.FieldError{Field: field + ".points", Message: "Poin tidak boleh negatif."})
+		}
+	}
+
+	return fieldErrors
+}
+
+func validateTeamSplitRules(rules []modelpostgre.TeamSplitRule) []modelmongo.FieldError {
+	fieldErrors := []modelmongo.FieldError{}
+	seenTypes := make(map[string]bool)
+
+	for i, rule := range rules {
+		field := fmt.Sprintf("team_split_rules[%d]", i)
+		if rule.AchievementType != "" && !modelmongo.IsValidAchievementType(rule.AchievementType) {
+			fieldErrors = append(fieldErrors, modelmongo.FieldError{Field: field + ".achievement_type", Message: "Achievement type tidak valid. Gunakan: academic, competition, organization, publication, certification, atau other."})
+		}
+		if seenTypes[rule.AchievementType] {
+			fieldErrors = append(fieldErrors, modelmongo.FieldError{Field: field + ".achievement_type", Message: "Hanya boleh ada satu aturan pembagian poin per achievement type (dan satu aturan default tanpa achievement type)."})
+		}
+		seenTypes[rule.AchievementType] = true
+
+		if !modelpostgre.IsValidTeamSplitMode(rule.Mode) {
+			fieldErrors = append(fieldErrors, modelmongo.FieldError{Field: field + ".mode", Message: "Mode pembagian poin tidak valid. Gunakan: full, equal, atau weighted."})
+		} else if rule.Mode == modelpostgre.TeamSplitModeWeighted {
+			if rule.LeaderWeight < 0 || rule.MemberWeight < 0 || rule.LeaderWeight+rule.MemberWeight == 0 {
+				fieldErrors = append(fieldErrors, modelmongo.FieldError{Field: field, Message: "Mode weighted membutuhkan leader_weight dan member_weight yang tidak negatif dan tidak keduanya 0."})
+			}
+		}
+	}
+
+	return fieldErrors
+}
+
+func pointCriteriaFromAchievement(achievementType string, details modelmongo.AchievementDetails) modelpostgre.PointCriteria {
+	criteria := modelpostgre.PointCriteria{
+		AchievementType: achievementType,
+		Rank:            details.Rank,
+	}
+	if details.CompetitionLevel != nil {
+		criteria.CompetitionLevel = *details.CompetitionLevel
+	}
+	if details.MedalType != nil {
+		criteria.MedalType = *details.MedalType
+	}
+	if details.PublicationType != nil {
+		criteria.PublicationType = *details.PublicationType
+	}
+	if details.Position != nil {
+		criteria.Position = *details.Position
+	}
+	return criteria
+}
+
+// calculateAchievementPoints menghitung poin prestasi dengan rubrik yang sedang diterbitkan dan
+// mengembalikan versi rubrik yang dipakai. Tanpa rubrik aktif, poin dan versi bernilai 0.
+func calculateAchievementPoints(postgresDB *sql.DB, achievementType string, details modelmongo.AchievementDetails) (int, int, error) {
+	rubric, err := repositorypostgre.GetPointRubricByStatus(postgresDB, modelpostgre.PointRubricStatusPublished)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, 0, nil
+		}
+		return 0, 0, err
+	}
+
+	return rubric.CalculatePoints(pointCriteriaFromAchievement(achievementType, details)), rubric.Version, nil
+}
@@ -0,0 +1,343 @@
+package service
+
+import (
+	"database/sql"
+	model "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repository "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/helper"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func GetRolesService(c *fiber.Ctx, db *sql.DB) error {
+	roles, err := repository.GetAllRoles(db)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil data role dari database. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.GetAllRolesResponse{
+		Status: "success",
+		Data:   roles,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func GetRoleByIDService(c *fiber.Ctx, db *sql.DB) error {
+	role, ok, err := getRoleFromParams(c, db)
+	if !ok {
+		return err
+	}
+
+	response := model.GetRoleByIDResponse{
+		Status: "success",
+		Data:   *role,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func CreateRoleService(c *fiber.Ctx, db *sql.DB) error {
+	var req model.CreateRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Format request body tidak valid. Pastikan JSON format benar. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Nama role wajib diisi.",
+			},
+		})
+	}
+
+	role, err := repository.CreateRole(db, req)
+	if err != nil {
+		if _, ok := helper.IsUniqueViolation(err); ok {
+			return helper.ConflictResponse(c, "Nama role sudah digunakan.")
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error menyimpan role ke database. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.CreateRoleResponse{
+		Status: "success",
+		Data:   *role,
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+func UpdateRoleService(c *fiber.Ctx, db *sql.DB) error {
+	id := c.Params("id")
+	if !helper.IsValidUUID(id) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "ID role tidak valid.",
+			},
+		})
+	}
+
+	var req model.UpdateRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Format request body tidak valid. Pastikan JSON format benar. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Nama role wajib diisi.",
+			},
+		})
+	}
+
+	role, err := repository.UpdateRole(db, id, req)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Role tidak ditemukan.",
+				},
+			})
+		}
+		if _, ok := helper.IsUniqueViolation(err); ok {
+			return helper.ConflictResponse(c, "Nama role sudah digunakan.")
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengupdate role. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.UpdateRoleResponse{
+		Status: "success",
+		Data:   *role,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func DeleteRoleService(c *fiber.Ctx, db *sql.DB) error {
+	id := c.Params("id")
+	if !helper.IsValidUUID(id) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "ID role tidak valid.",
+			},
+		})
+	}
+
+	err := repository.DeleteRole(db, id, model.PermissionUserManage)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Role tidak ditemukan.",
+				},
+			})
+		}
+		if err == repository.ErrLastPermissionHolder {
+			return helper.ConflictResponse(c, "Role ini memiliki permission '"+model.PermissionUserManage+"' dan tidak ada user aktif di role lain yang memilikinya. Role tidak dapat dihapus.")
+		}
+		if _, ok := helper.IsForeignKeyViolation(err); ok {
+			return helper.ConflictResponse(c, "Role masih digunakan oleh user dan tidak dapat dihapus.")
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error menghapus role. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.DeleteRoleResponse{
+		Status: "success",
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func GetRolePermissionsService(c *fiber.Ctx, db *sql.DB) error {
+	role, ok, err := getRoleFromParams(c, db)
+	if !ok {
+		return err
+	}
+
+	permissions, err := repository.GetRolePermissions(db, role.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil permission role. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.GetRolePermissionsResponse{
+		Status: "success",
+		Data:   permissions,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func GrantRolePermissionService(c *fiber.Ctx, db *sql.DB) error {
+	role, ok, err := getRoleFromParams(c, db)
+	if !ok {
+		return err
+	}
+
+	var req model.CreateRolePermissionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Format request body tidak valid. Pastikan JSON format benar. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	req.RoleID = role.ID
+	if !helper.IsValidUUID(req.PermissionID) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Permission ID wajib diisi dengan UUID yang valid.",
+			},
+		})
+	}
+
+	rolePermission, err := repository.GrantRolePermission(db, req)
+	if err != nil {
+		if _, ok := helper.IsUniqueViolation(err); ok {
+			return helper.ConflictResponse(c, "Role sudah memiliki permission ini.")
+		}
+		if _, ok := helper.IsForeignKeyViolation(err); ok {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Permission tidak ditemukan.",
+				},
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error menambahkan permission ke role. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.CreateRolePermissionResponse{
+		Status: "success",
+		Data:   *rolePermission,
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+func RevokeRolePermissionService(c *fiber.Ctx, db *sql.DB) error {
+	role, ok, err := getRoleFromParams(c, db)
+	if !ok {
+		return err
+	}
+
+	permissionID := c.Params("permissionId")
+	if !helper.IsValidUUID(permissionID) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "ID permission tidak valid.",
+			},
+		})
+	}
+
+	err = repository.RevokeRolePermission(db, role.ID, permissionID, model.PermissionUserManage)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Role tidak memiliki permission ini.",
+				},
+			})
+		}
+		if err == repository.ErrLastPermissionHolder {
+			return helper.ConflictResponse(c, "Permission '"+model.PermissionUserManage+"' tidak dapat dicabut karena tidak ada user aktif di role lain yang memilikinya.")
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mencabut permission dari role. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.DeleteRolePermissionResponse{
+		Status: "success",
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func getRoleFromParams(c *fiber.Ctx, db *sql.DB) (*model.Role, bool, error) {
+	id := c.Params("id")
+	if !helper.IsValidUUID(id) {
+		return nil, false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "ID role tidak valid.",
+			},
+		})
+	}
+
+	role, err := repository.GetRoleByID(db, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Role tidak ditemukan.",
+				},
+			})
+		}
+		return nil, false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil data role. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	return role, true, nil
+}
@@ -0,0 +1,502 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"os"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	utilspostgre "sistem-pelaporan-prestasi-mahasiswa/utils/postgre"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	demoSeedPassword         = "12345678"
+	seedAchievementBatchSize = 500
+	seedDepartment           = "Teknik Informatika"
+)
+
+type seedAchievement struct {
+	Document  modelmongo.Achievement
+	Reference modelpostgre.SeedAchievementReference
+}
+
+// SeedFixture mengisi database dengan fixture bernama. Seeding hanya berjalan pada database yang
+// ditandai sebagai non-production di app_settings dan ditolak jika APP_ENV bernilai production.
+func SeedFixture(postgresDB *sql.DB, mongoDB *mongo.Database, fixture string, opts modelpostgre.SeedOptions) (*modelpostgre.SeedResult, error) {
+	if err := ensureSeedAllowed(postgresDB); err != nil {
+		return nil, err
+	}
+
+	result := &modelpostgre.SeedResult{
+		Fixture:     fixture,
+		Credentials: []modelpostgre.SeedCredential{},
+	}
+
+	var err error
+	switch fixture {
+	case modelpostgre.SeedFixtureMinimal:
+		err = seedMinimalFixture(postgresDB, opts, result)
+	case modelpostgre.SeedFixtureDemo:
+		err = seedDemoFixture(postgresDB, mongoDB, opts, result)
+	case modelpostgre.SeedFixtureLoadTest:
+		err = seedLoadTestFixture(postgresDB, mongoDB, opts, result)
+	default:
+		return nil, fmt.Errorf("unknown fixture %q, use one of: %s", fixture, strings.Join(modelpostgre.SeedFixtures, ", "))
+	}
+
+	return result, err
+}
+
+// ensureSeedAllowed menolak seeding kecuali database ditandai secara eksplisit sebagai development
+// atau staging. Database tanpa penanda ditolak karena bisa saja database production yang diadopsi
+// dengan `migrate baseline`.
+func ensureSeedAllowed(postgresDB *sql.DB) error {
+	if strings.EqualFold(os.Getenv("APP_ENV"), modelpostgre.EnvironmentProduction) {
+		return fmt.Errorf("refusing to seed: APP_ENV is production")
+	}
+
+	environment, err := repositorypostgre.GetAppSetting(postgresDB, modelpostgre.AppSettingEnvironment)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("refusing to seed: database environment is not set, run `go run cmd/migrate/main.go environment development` on non-production databases")
+	}
+	if err != nil {
+		return fmt.Errorf("read database environment (run `migrate up` first): %w", err)
+	}
+
+	if environment == modelpostgre.EnvironmentProduction || !modelpostgre.IsValidEnvironment(environment) {
+		return fmt.Errorf("refusing to seed: database is flagged as %s", environment)
+	}
+
+	return nil
+}
+
+// seedMinimalFixture hanya membuat akun admin. Tanpa --password, password acak dibuat dan
+// dikembalikan di hasil.
+func seedMinimalFixture(postgresDB *sql.DB, opts modelpostgre.SeedOptions, result *modelpostgre.SeedResult) error {
+	password := opts.Password
+	generated := false
+	if password == "" {
+		var err error
+		if password, err = utilspostgre.GeneratePassword(16); err != nil {
+			return err
+		}
+		generated = true
+	}
+
+	_, created, err := seedUserWithPassword(postgresDB, result, "admin", "admin@gmail.com", "Administrator", "Admin", password)
+	if err != nil {
+		return err
+	}
+
+	if created && generated {
+		result.Credentials = append(result.Credentials, modelpostgre.SeedCredential{Username: "admin", Password: password})
+	}
+
+	return nil
+}
+
+// seedDemoFixture membuat data tutorial di README: 1 admin, 3 dosen wali, 3 mahasiswa, dan
+// beberapa prestasi dengan status berbeda. Prestasi hanya dibuat untuk mahasiswa yang belum
+// memiliki prestasi sehingga fixture aman dijalankan ulang.
+func seedDemoFixture(postgresDB *sql.DB, mongoDB *mongo.Database, opts modelpostgre.SeedOptions, result *modelpostgre.SeedResult) error {
+	password := opts.Password
+	if password == "" {
+		password = demoSeedPassword
+	}
+
+	if _, _, err := seedUserWithPassword(postgresDB, result, "admin", "admin@gmail.com", "Administrator", "Admin", password); err != nil {
+		return err
+	}
+
+	lecturers := []struct {
+		Username, FullName, LecturerID string
+	}{
+		{"dosen1", "Prof. Dr. Ahmad Wijaya, S.T., M.T.", "DOS001"},
+		{"dosen2", "Dr. Siti Nurhaliza, S.Kom., M.Kom.", "DOS002"},
+		{"dosen3", "Dr. Budi Santoso, S.T., M.Sc.", "DOS003"},
+	}
+	students := []struct {
+		Username, FullName, StudentID string
+	}{
+		{"mahasiswa1", "Andi Pratama", "202410001"},
+		{"mahasiswa2", "Budi Setiawan", "202410002"},
+		{"mahasiswa3", "Citra Dewi", "202410003"},
+	}
+
+	lecturerIDs := make([]string, len(lecturers))
+	lecturerUserIDs := make([]string, len(lecturers))
+	for i, lecturer := range lecturers {
+		userID, _, err := seedUserWithPassword(postgresDB, result, lecturer.Username, lecturer.Username+"@gmail.com", lecturer.FullName, "Dosen Wali", password)
+		if err != nil {
+			return err
+		}
+
+		lecturerID, created, err := repositorypostgre.SeedLecturer(postgresDB, userID, lecturer.LecturerID, seedDepartment)
+		if err != nil {
+			return err
+		}
+		if created {
+			result.Lecturers++
+		}
+
+		lecturerIDs[i] = lecturerID
+		lecturerUserIDs[i] = userID
+	}
+
+	now := time.Now()
+	var achievements []seedAchievement
+	for i, student := range students {
+		userID, _, err := seedUserWithPassword(postgresDB, result, student.Username, student.Username+"@gmail.com", student.FullName, "Mahasiswa", password)
+		if err != nil {
+			return err
+		}
+
+		advisorID := lecturerIDs[i]
+		studentID, created, err := repositorypostgre.SeedStudent(postgresDB, userID, student.StudentID, seedDepartment, "2024", &advisorID)
+		if err != nil {
+			return err
+		}
+		if created {
+			result.Students++
+		}
+
+		count, err := repositorypostgre.CountAchievementReferencesByStudentID(postgresDB, studentID)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+
+		advisorUserID := lecturerUserIDs[i]
+		for j, item := range demoAchievements(i, now) {
+			item.Document.StudentID = studentID
+			item.Reference.StudentID = studentID
+			item.Reference.StudentUserID = userID
+			item.Reference.AdvisorUserID = &advisorUserID
+			item.Reference.CreatedAt = now.AddDate(0, 0, -30+j*7)
+			achievements = append(achievements, item)
+		}
+	}
+
+	return insertSeedAchievements(postgresDB, mongoDB, achievements, result)
+}
+
+func demoAchievements(studentIndex int, now time.Time) []seedAchievement {
+	stringPtr := func(value string) *string { return &value }
+	intPtr := func(value int) *int { return &value }
+	rejectionNote := "Lampirkan sertifikat resmi dari penyelenggara."
+
+	switch studentIndex {
+	case 0:
+		return []seedAchievement{
+			newSeedAchievement(modelmongo.AchievementTypeCompetition, "Juara 1 Lomba Programming Nasional",
+				"Meraih juara 1 dalam National Programming Contest 2024",
+				modelmongo.AchievementDetails{
+					CompetitionName:  stringPtr("National Programming Contest"),
+					CompetitionLevel: stringPtr(modelmongo.CompetitionLevelNational),
+					Rank:             intPtr(1),
+					MedalType:        stringPtr("gold"),
+				}, []string{"programming", "competition"}, modelpostgre.AchievementStatusVerified, nil),
+			newSeedAchievement(modelmongo.AchievementTypePublication, "Publikasi Jurnal Machine Learning",
+				"Artikel tentang klasifikasi citra dengan deep learning",
+				modelmongo.AchievementDetails{
+					PublicationType:  stringPtr(modelmongo.PublicationTypeJournal),
+					PublicationTitle: stringPtr("Klasifikasi Citra Daun dengan Convolutional Neural Network"),
+					Authors:          []string{"Andi Pratama", "Prof. Dr. Ahmad Wijaya, S.T., M.T."},
+					Publisher:        stringPtr("Jurnal Teknologi Informasi"),
+				}, []string{"machine-learning", "research"}, modelpostgre.AchievementStatusSubmitted, nil),
+		}
+	case 1:
+		return []seedAchievement{
+			newSeedAchievement(modelmongo.AchievementTypeOrganization, "Ketua Himpunan Mahasiswa Informatika",
+				"Memimpin himpunan mahasiswa selama satu periode kepengurusan",
+				modelmongo.AchievementDetails{
+					OrganizationName: stringPtr("Himpunan Mahasiswa Informatika"),
+					Position:         stringPtr("Ketua"),
+					Period:           &modelmongo.Period{Start: now.AddDate(-1, 0, 0), End: now},
+				}, []string{"leadership"}, modelpostgre.AchievementStatusDraft, nil),
+			newSeedAchievement(modelmongo.AchievementTypeCertification, "AWS Certified Cloud Practitioner",
+				"Sertifikasi dasar layanan cloud Amazon Web Services",
+				modelmongo.AchievementDetails{
+					CertificationName: stringPtr("AWS Certified Cloud Practitioner"),
+					IssuedBy:          stringPtr("Amazon Web Services"),
+				}, []string{"cloud", "certification"}, modelpostgre.AchievementStatusVerified, nil),
+		}
+	default:
+		return []seedAchievement{
+			newSeedAchievement(modelmongo.AchievementTypeCompetition, "Juara 2 Lomba UI/UX Regional",
+				"Meraih juara 2 dalam kompetisi desain UI/UX tingkat regional",
+				modelmongo.AchievementDetails{
+					CompetitionName:  stringPtr("Regional UI/UX Challenge"),
+					CompetitionLevel: stringPtr(modelmongo.CompetitionLevelRegional),
+					Rank:             intPtr(2),
+				}, []string{"design"}, modelpostgre.AchievementStatusRejected, &rejectionNote),
+			newSeedAchievement(modelmongo.AchievementTypeAcademic, "IPK Tertinggi Angkatan 2024",
+				"Meraih IPK tertinggi pada semester ganjil",
+				modelmongo.AchievementDetails{}, []string{"academic"}, modelpostgre.AchievementStatusDraft, nil),
+		}
+	}
+}
+
+// seedLoadTestFixture membuat sejumlah dosen, mahasiswa, dan prestasi acak dengan prefix unik
+// per eksekusi. Seluruh akun pada satu eksekusi memakai password yang sama agar hashing bcrypt
+// tidak mendominasi waktu seeding.
+func seedLoadTestFixture(postgresDB *sql.DB, mongoDB *mongo.Database, opts modelpostgre.SeedOptions, result *modelpostgre.SeedResult) error {
+	if opts.Lecturers <= 0 || opts.Students <= 0 || opts.AchievementsPerStudent < 0 {
+		return fmt.Errorf("load-test requires at least 1 lecturer and 1 student, and a non-negative number of achievements")
+	}
+
+	password := opts.Password
+	if password == "" {
+		var err error
+		if password, err = utilspostgre.GeneratePassword(16); err != nil {
+			return err
+		}
+	}
+
+	passwordHash, err := utilspostgre.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	batch := strconv.FormatInt(time.Now().Unix(), 36)
+	prefix := "lt" + batch
+	result.Credentials = append(result.Credentials, modelpostgre.SeedCredential{Username: prefix + "_*", Password: password})
+
+	random := rand.New(rand.NewSource(opts.RandomSeed))
+
+	lecturerIDs := make([]string, opts.Lecturers)
+	lecturerUserIDs := make([]string, opts.Lecturers)
+	for i := range lecturerIDs {
+		username := fmt.Sprintf("%s_dosen_%04d", prefix, i+1)
+		userID, created, err := repositorypostgre.SeedUser(postgresDB, username, username+"@loadtest.local", passwordHash, fmt.Sprintf("Dosen Load Test %d", i+1), "Dosen Wali")
+		if err != nil {
+			return err
+		}
+		if created {
+			result.Users++
+		}
+
+		lecturerID, created, err := repositorypostgre.SeedLecturer(postgresDB, userID, fmt.Sprintf("LTD%s%04d", strings.ToUpper(batch), i+1), seedDepartment)
+		if err != nil {
+			return err
+		}
+		if created {
+			result.Lecturers++
+		}
+
+		lecturerIDs[i] = lecturerID
+		lecturerUserIDs[i] = userID
+	}
+
+	rubric, err := repositorypostgre.GetPointRubricByStatus(postgresDB, modelpostgre.PointRubricStatusPublished)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	now := time.Now()
+	achievements := make([]seedAchievement, 0, seedAchievementBatchSize)
+	for i := 0; i < opts.Students; i++ {
+		username := fmt.Sprintf("%s_mhs_%05d", prefix, i+1)
+		userID, created, err := repositorypostgre.SeedUser(postgresDB, username, username+"@loadtest.local", passwordHash, fmt.Sprintf("Mahasiswa Load Test %d", i+1), "Mahasiswa")
+		if err != nil {
+			return err
+		}
+		if created {
+			result.Users++
+		}
+
+		advisorIndex := i % opts.Lecturers
+		advisorID := lecturerIDs[advisorIndex]
+		academicYear := strconv.Itoa(now.Year() - random.Intn(4))
+		studentID, created, err := repositorypostgre.SeedStudent(postgresDB, userID, fmt.Sprintf("LT%s%05d", strings.ToUpper(batch), i+1), seedDepartment, academicYear, &advisorID)
+		if err != nil {
+			return err
+		}
+		if created {
+			result.Students++
+		}
+
+		advisorUserID := lecturerUserIDs[advisorIndex]
+		for j := 0; j < opts.AchievementsPerStudent; j++ {
+			item := randomSeedAchievement(random, now)
+			item.Document.StudentID = studentID
+			item.Reference.StudentID = studentID
+			item.Reference.StudentUserID = userID
+			item.Reference.AdvisorUserID = &advisorUserID
+			item.Reference.CreatedAt = now.Add(-time.Duration(random.Intn(365*24)) * time.Hour)
+			if rubric != nil {
+				item.Document.Points = rubric.CalculatePoints(pointCriteriaFromAchievement(item.Document.AchievementType, item.Document.Details))
+				item.Document.PointRubricVersion = rubric.Version
+			}
+			achievements = append(achievements, item)
+
+			if len(achievements) == seedAchievementBatchSize {
+				if err := insertSeedAchievements(postgresDB, mongoDB, achievements, result); err != nil {
+					return err
+				}
+				achievements = achievements[:0]
+			}
+		}
+	}
+
+	return insertSeedAchievements(postgresDB, mongoDB, achievements, result)
+}
+
+func randomSeedAchievement(random *rand.Rand, now time.Time) seedAchievement {
+	stringPtr := func(value string) *string { return &value }
+	pick := func(values ...string) string { return values[random.Intn(len(values))] }
+
+	statuses := []string{
+		modelpostgre.AchievementStatusDraft, modelpostgre.AchievementStatusDraft, modelpostgre.AchievementStatusDraft,
+		modelpostgre.AchievementStatusSubmitted, modelpostgre.AchievementStatusSubmitted,
+		modelpostgre.AchievementStatusVerified, modelpostgre.AchievementStatusVerified,
+		modelpostgre.AchievementStatusVerified, modelpostgre.AchievementStatusVerified,
+		modelpostgre.AchievementStatusRejected,
+	}
+	status := statuses[random.Intn(len(statuses))]
+	var rejectionNote *string
+	if status == modelpostgre.AchievementStatusRejected {
+		rejectionNote = stringPtr(pick("Bukti prestasi belum lengkap.", "Lampirkan sertifikat resmi.", "Tanggal kegiatan tidak sesuai."))
+	}
+
+	topic := pick("Programming", "Data Science", "Keamanan Siber", "UI/UX", "Robotika", "Internet of Things", "Cloud Computing")
+	var details modelmongo.AchievementDetails
+	var achievementType, title string
+
+	switch random.Intn(6) {
+	case 0:
+		achievementType = modelmongo.AchievementTypeCompetition
+		level := pick(modelmongo.CompetitionLevelInternational, modelmongo.CompetitionLevelNational, modelmongo.CompetitionLevelRegional, modelmongo.CompetitionLevelLocal)
+		rank := random.Intn(3) + 1
+		details = modelmongo.AchievementDetails{
+			CompetitionName:  stringPtr("Kompetisi " + topic),
+			CompetitionLevel: &level,
+			Rank:             &rank,
+			MedalType:        stringPtr([]string{"gold", "silver", "bronze"}[rank-1]),
+		}
+		title = fmt.Sprintf("Juara %d Kompetisi %s Tingkat %s", rank, topic, level)
+	case 1:
+		achievementType = modelmongo.AchievementTypePublication
+		details = modelmongo.AchievementDetails{
+			PublicationType:  stringPtr(pick(modelmongo.PublicationTypeJournal, modelmongo.PublicationTypeConference, modelmongo.PublicationTypeBook)),
+			PublicationTitle: stringPtr("Penerapan " + topic + " pada Sistem Informasi Kampus"),
+			Authors:          []string{"Mahasiswa Load Test", "Dosen Pembimbing"},
+		}
+		title = "Publikasi " + topic
+	case 2:
+		achievementType = modelmongo.AchievementTypeOrganization
+		start := now.AddDate(-1-random.Intn(2), 0, 0)
+		details = modelmongo.AchievementDetails{
+			OrganizationName: stringPtr("Komunitas " + topic),
+			Position:         stringPtr(pick("Ketua", "Wakil Ketua", "Sekretaris", "Anggota")),
+			Period:           &modelmongo.Period{Start: start, End: start.AddDate(1, 0, 0)},
+		}
+		title = "Pengurus Komunitas " + topic
+	case 3:
+		achievementType = modelmongo.AchievementTypeCertification
+		details = modelmongo.AchievementDetails{
+			CertificationName: stringPtr("Sertifikasi " + topic),
+			IssuedBy:          stringPtr(pick("BNSP", "Dicoding", "Cisco", "Google", "Microsoft")),
+		}
+		title = "Sertifikasi " + topic
+	case 4:
+		achievementType = modelmongo.AchievementTypeAcademic
+		title = "Penghargaan Akademik " + topic
+	default:
+		achievementType = modelmongo.AchievementTypeOther
+		title = "Kegiatan Pengabdian " + topic
+	}
+
+	return newSeedAchievement(achievementType, title, "Data load test: "+strings.ToLower(title), details,
+		[]string{strings.ToLower(strings.ReplaceAll(topic, " ", "-"))}, status, rejectionNote)
+}
+
+func newSeedAchievement(achievementType string, title string, description string, details modelmongo.AchievementDetails, tags []string, status string, rejectionNote *string) seedAchievement {
+	return seedAchievement{
+		Document: modelmongo.Achievement{
+			ID:              primitive.NewObjectID(),
+			AchievementType: achievementType,
+			Title:           title,
+			Description:     description,
+			Details:         details,
+			Tags:            tags,
+		},
+		Reference: modelpostgre.SeedAchievementReference{
+			Status:        status,
+			RejectionNote: rejectionNote,
+		},
+	}
+}
+
+// insertSeedAchievements menyimpan dokumen ke MongoDB lalu reference-nya ke PostgreSQL. Jika
+// PostgreSQL gagal, dokumen yang baru disimpan dihapus kembali agar tidak menjadi orphan.
+// Poin yang belum dihitung diisi dari rubrik yang sedang diterbitkan.
+func insertSeedAchievements(postgresDB *sql.DB, mongoDB *mongo.Database, items []seedAchievement, result *modelpostgre.SeedResult) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	documents := make([]modelmongo.Achievement, len(items))
+	references := make([]modelpostgre.SeedAchievementReference, len(items))
+	ids := make([]primitive.ObjectID, len(items))
+	for i, item := range items {
+		document := item.Document
+		if document.PointRubricVersion == 0 {
+			points, version, err := calculateAchievementPoints(postgresDB, document.AchievementType, document.Details)
+			if err != nil {
+				return err
+			}
+			document.Points = points
+			document.PointRubricVersion = version
+		}
+		document.CreatedAt = item.Reference.CreatedAt
+		document.UpdatedAt = item.Reference.CreatedAt
+
+		reference := item.Reference
+		reference.MongoAchievementID = document.ID.Hex()
+
+		documents[i] = document
+		references[i] = reference
+		ids[i] = document.ID
+	}
+
+	if err := repositorymongo.CreateAchievements(mongoDB, documents); err != nil {
+		return err
+	}
+
+	if err := repositorypostgre.CreateSeedAchievementReferences(postgresDB, references); err != nil {
+		repositorymongo.PurgeAchievements(mongoDB, ids)
+		return err
+	}
+
+	result.Achievements += len(items)
+	return nil
+}
+
+func seedUserWithPassword(postgresDB *sql.DB, result *modelpostgre.SeedResult, username string, email string, fullName string, roleName string, password string) (string, bool, error) {
+	passwordHash, err := utilspostgre.HashPassword(password)
+	if err != nil {
+		return "", false, err
+	}
+
+	userID, created, err := repositorypostgre.SeedUser(postgresDB, username, email, passwordHash, fullName, roleName)
+	if err != nil {
+		return "", false, err
+	}
+	if created {
+		result.Users++
+	}
+
+	return userID, created, nil
+}
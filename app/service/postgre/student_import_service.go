@@ -0,0 +1,352 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	model "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repository "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/helper"
+	utilspostgre "sistem-pelaporan-prestasi-mahasiswa/utils/postgre"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	maxStudentImportFileSize     = 10 * 1024 * 1024
+	studentInitialPasswordLength = 12
+)
+
+// errStudentRoleUnresolved dikembalikan ketika role untuk akun mahasiswa baru tidak dapat ditentukan.
+var errStudentRoleUnresolved = errors.New("role mahasiswa tidak dapat ditentukan")
+
+// ImportStudentsService membuat atau memperbarui akun mahasiswa dari file CSV/XLSX (field
+// multipart "file"). Form dry_run=true hanya memvalidasi. Query format=csv mengembalikan laporan
+// sebagai file CSV yang dapat diunduh, termasuk password awal akun yang baru dibuat.
+func ImportStudentsService(c *fiber.Ctx, db *sql.DB) error {
+	format := c.Query("format", "json")
+	if format != "json" && format != "csv" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Format laporan tidak valid. Gunakan json atau csv.",
+			},
+		})
+	}
+
+	dryRun := false
+	if value := c.FormValue("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Nilai dry_run tidak valid. Gunakan true atau false.",
+				},
+			})
+		}
+		dryRun = parsed
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "File tidak ditemukan. Pastikan field name adalah 'file'.",
+			},
+		})
+	}
+	if file.Size > maxStudentImportFileSize {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Ukuran file terlalu besar. Maksimal 10MB.",
+			},
+		})
+	}
+
+	content, err := file.Open()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error membuka file. Detail: " + err.Error(),
+			},
+		})
+	}
+	defer content.Close()
+
+	records, err := helper.ReadSpreadsheet(file.Filename, content)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "File import tidak dapat dibaca. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	rows, err := model.ParseStudentImportRecords(records)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Format file import tidak valid. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	report, err := ImportStudents(db, rows, dryRun)
+	if err != nil {
+		if errors.Is(err, errStudentRoleUnresolved) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": err.Error(),
+				},
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengimport data mahasiswa. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	if format == "csv" {
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"student-import-%s.csv\"", time.Now().Format("20060102-150405")))
+		c.Set(fiber.HeaderCacheControl, "no-store")
+		writer := csv.NewWriter(c)
+		if err := writer.WriteAll(report.CSVRecords()); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	response := model.ImportStudentsResponse{
+		Status: "success",
+		Data:   *report,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// ImportStudents melakukan upsert akun mahasiswa dengan NIM (students.student_id) sebagai kunci.
+// NIM baru membuat user dengan password awal acak dan role mahasiswa, yaitu satu-satunya role yang
+// memiliki permission achievement:read:own (sama dengan syarat profil mahasiswa); NIM yang sudah ada hanya
+// memperbarui kolom yang diisi, tanpa mengubah password. Menjalankan file yang sama dua kali
+// menghasilkan status unchanged. Dosen wali dicari dari NIP (lecturers.lecturer_id).
+func ImportStudents(db *sql.DB, rows []model.StudentImportRow, dryRun bool) (*model.StudentImportReport, error) {
+	roleID, err := resolveStudentRole(db)
+	if err != nil {
+		return nil, err
+	}
+
+	studentIDs := make([]string, 0, len(rows))
+	nips := make([]string, 0, len(rows))
+	for _, row := range rows {
+		studentIDs = append(studentIDs, row.StudentID)
+		if row.AdvisorNIP != "" {
+			nips = append(nips, row.AdvisorNIP)
+		}
+	}
+
+	accounts, err := repository.GetStudentAccountsByStudentIDs(db, studentIDs)
+	if err != nil {
+		return nil, err
+	}
+	advisors, err := repository.GetLecturerIDsByNIPs(db, nips)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.StudentImportReport{
+		DryRun: dryRun,
+		Total:  len(rows),
+		Rows:   make([]model.StudentImportRowResult, 0, len(rows)),
+	}
+
+	seen := make(map[string]int)
+	for _, row := range rows {
+		var result model.StudentImportRowResult
+		if firstRow, ok := seen[row.StudentID]; ok && row.StudentID != "" {
+			result = model.StudentImportRowResult{
+				Row:       row.Row,
+				StudentID: row.StudentID,
+				Status:    model.StudentImportStatusFailed,
+				Errors:    []string{fmt.Sprintf("NIM sudah muncul di baris %d.", firstRow)},
+			}
+		} else {
+			seen[row.StudentID] = row.Row
+			result = importStudentRow(db, row, roleID, accounts, advisors, dryRun)
+		}
+
+		switch result.Status {
+		case model.StudentImportStatusCreated:
+			report.Created++
+		case model.StudentImportStatusUpdated:
+			report.Updated++
+		case model.StudentImportStatusUnchanged:
+			report.Unchanged++
+		default:
+			report.Failed++
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	return report, nil
+}
+
+func importStudentRow(db *sql.DB, row model.StudentImportRow, roleID string, accounts map[string]model.StudentAccount, advisors map[string]string, dryRun bool) model.StudentImportRowResult {
+	result := model.StudentImportRowResult{
+		Row:       row.Row,
+		StudentID: row.StudentID,
+		Status:    model.StudentImportStatusFailed,
+		Errors:    []string{},
+	}
+
+	existing, exists := accounts[row.StudentID]
+	account := existing
+	account.StudentID = row.StudentID
+	if !exists && row.Username == "" {
+		account.Username = row.StudentID
+	}
+	for _, value := range []struct {
+		target *string
+		source string
+	}{
+		{&account.FullName, row.FullName},
+		{&account.Email, row.Email},
+		{&account.Username, row.Username},
+		{&account.ProgramStudy, row.ProgramStudy},
+		{&account.AcademicYear, row.AcademicYear},
+	} {
+		if value.source != "" {
+			*value.target = value.source
+		}
+	}
+	result.Username = account.Username
+	result.FullName = account.FullName
+
+	if row.AdvisorNIP != "" {
+		advisorID, ok := advisors[row.AdvisorNIP]
+		if !ok {
+			result.Errors = append(result.Errors, "Dosen wali dengan NIP "+row.AdvisorNIP+" tidak ditemukan.")
+		}
+		account.AdvisorID = advisorID
+	}
+
+	result.Errors = append(result.Errors, validateStudentAccount(account)...)
+	if len(result.Errors) > 0 {
+		return result
+	}
+
+	if exists {
+		if account == existing {
+			result.Status = model.StudentImportStatusUnchanged
+			result.Errors = nil
+			return result
+		}
+		if !dryRun {
+			if err := repository.UpdateStudentAccount(db, account); err != nil {
+				result.Errors = append(result.Errors, studentImportErrorMessage(err))
+				return result
+			}
+		}
+		result.Status = model.StudentImportStatusUpdated
+		result.Errors = nil
+		return result
+	}
+
+	if !dryRun {
+		password, err := utilspostgre.GeneratePassword(studentInitialPasswordLength)
+		if err != nil {
+			result.Errors = append(result.Errors, "Error membuat password awal. Detail: "+err.Error())
+			return result
+		}
+		passwordHash, err := utilspostgre.HashPassword(password)
+		if err != nil {
+			result.Errors = append(result.Errors, "Error membuat password awal. Detail: "+err.Error())
+			return result
+		}
+		if _, err := repository.CreateStudentAccount(db, account, roleID, passwordHash); err != nil {
+			result.Errors = append(result.Errors, studentImportErrorMessage(err))
+			return result
+		}
+		result.InitialPassword = password
+	}
+	result.Status = model.StudentImportStatusCreated
+	result.Errors = nil
+	return result
+}
+
+// resolveStudentRole memilih role untuk akun mahasiswa baru berdasarkan permission, bukan nama role,
+// sehingga import tetap berjalan setelah role diganti namanya. Role harus tunggal agar akun tidak
+// dibuat dengan role yang salah.
+func resolveStudentRole(db *sql.DB) (string, error) {
+	permission := model.AchievementScopePermission(model.AchievementActionRead, model.AchievementScopeOwn)
+	roleIDs, err := repository.GetRoleIDsByPermission(db, permission)
+	if err != nil {
+		return "", err
+	}
+
+	switch len(roleIDs) {
+	case 0:
+		return "", fmt.Errorf("%w: tidak ada role dengan permission '%s'", errStudentRoleUnresolved, permission)
+	case 1:
+		return roleIDs[0], nil
+	default:
+		return "", fmt.Errorf("%w: lebih dari satu role memiliki permission '%s'", errStudentRoleUnresolved, permission)
+	}
+}
+
+func validateStudentAccount(account model.StudentAccount) []string {
+	errors := []string{}
+	if account.StudentID == "" {
+		errors = append(errors, "NIM wajib diisi.")
+	} else if len(account.StudentID) > 20 {
+		errors = append(errors, "NIM maksimal 20 karakter.")
+	}
+	if account.FullName == "" {
+		errors = append(errors, "Nama lengkap wajib diisi untuk mahasiswa baru.")
+	} else if len(account.FullName) > 100 {
+		errors = append(errors, "Nama lengkap maksimal 100 karakter.")
+	}
+	if account.Email == "" {
+		errors = append(errors, "Email wajib diisi untuk mahasiswa baru.")
+	} else if !helper.IsValidEmail(account.Email) || len(account.Email) > 100 {
+		errors = append(errors, "Email tidak valid.")
+	}
+	if len(account.Username) > 50 {
+		errors = append(errors, "Username maksimal 50 karakter.")
+	}
+	if len(account.ProgramStudy) > 100 {
+		errors = append(errors, "Program studi maksimal 100 karakter.")
+	}
+	if len(account.AcademicYear) > 10 {
+		errors = append(errors, "Angkatan maksimal 10 karakter.")
+	}
+	return errors
+}
+
+func studentImportErrorMessage(err error) string {
+	if constraint, ok := helper.IsUniqueViolation(err); ok {
+		switch constraint {
+		case "users_username_key":
+			return "Username sudah dipakai user lain."
+		case "users_email_key":
+			return "Email sudah dipakai user lain."
+		case "students_student_id_key":
+			return "NIM sudah terdaftar. Jalankan ulang import untuk memperbarui data."
+		}
+		return "Data bentrok dengan data yang sudah ada (" + constraint + ")."
+	}
+	return "Error menyimpan data mahasiswa. Detail: " + err.Error()
+}
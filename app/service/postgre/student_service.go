@@ -0,0 +1,348 @@
+package service
+
+import (
+	"database/sql"
+	model "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repository "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/helper"
+	utilspostgre "sistem-pelaporan-prestasi-mahasiswa/utils/postgre"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func GetStudentsService(c *fiber.Ctx, db *sql.DB) error {
+	page, limit := helper.ValidatePagination(helper.GetQueryInt(c, "page", 1), helper.GetQueryInt(c, "limit", 10))
+
+	filter := model.StudentFilter{
+		Search:       helper.SanitizeSearch(c.Query("search")),
+		AdvisorID:    c.Query("advisor_id"),
+		AcademicYear: c.Query("academic_year"),
+		Limit:        limit,
+		Offset:       helper.CalculateOffset(page, limit),
+	}
+
+	if filter.AdvisorID != "" && !helper.IsValidUUID(filter.AdvisorID) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Advisor ID tidak valid.",
+			},
+		})
+	}
+
+	students, total, err := repository.GetAllStudents(db, filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil data mahasiswa dari database. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.GetAllStudentsResponse{
+		Status:     "success",
+		Data:       students,
+		Pagination: model.NewPagination(page, limit, total),
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func GetStudentByIDService(c *fiber.Ctx, db *sql.DB) error {
+	id := c.Params("id")
+	if !helper.IsValidUUID(id) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "ID mahasiswa tidak valid.",
+			},
+		})
+	}
+
+	student, err := repository.GetStudentByID(db, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Data mahasiswa tidak ditemukan.",
+				},
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil data mahasiswa dari database. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.GetStudentByIDResponse{
+		Status: "success",
+		Data:   *student,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func CreateStudentService(c *fiber.Ctx, db *sql.DB) error {
+	var req model.CreateStudentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Format request body tidak valid. Pastikan JSON format benar. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	req.StudentID = strings.TrimSpace(req.StudentID)
+	if req.UserID == "" || req.StudentID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "User ID dan student_id (NIM) wajib diisi.",
+			},
+		})
+	}
+
+	if ok, err := validateProfileUser(c, db, req.UserID, model.AchievementScopePermission(model.AchievementActionRead, model.AchievementScopeOwn)); !ok {
+		return err
+	}
+
+	if ok, err := validateAdvisor(c, db, req.AdvisorID); !ok {
+		return err
+	}
+
+	student, err := repository.CreateStudent(db, req)
+	if err != nil {
+		if constraint, ok := helper.IsUniqueViolation(err); ok {
+			return helper.ConflictResponse(c, studentConflictMessage(constraint))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error menyimpan data mahasiswa ke database. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.CreateStudentResponse{
+		Status: "success",
+		Data:   *student,
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+func UpdateStudentService(c *fiber.Ctx, db *sql.DB) error {
+	id := c.Params("id")
+	if !helper.IsValidUUID(id) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "ID mahasiswa tidak valid.",
+			},
+		})
+	}
+
+	var req model.UpdateStudentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Format request body tidak valid. Pastikan JSON format benar. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	req.StudentID = strings.TrimSpace(req.StudentID)
+	if req.StudentID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Student ID (NIM) wajib diisi.",
+			},
+		})
+	}
+
+	if ok, err := validateAdvisor(c, db, req.AdvisorID); !ok {
+		return err
+	}
+
+	student, err := repository.UpdateStudent(db, id, req)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Data mahasiswa tidak ditemukan.",
+				},
+			})
+		}
+		if constraint, ok := helper.IsUniqueViolation(err); ok {
+			return helper.ConflictResponse(c, studentConflictMessage(constraint))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengupdate data mahasiswa. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.UpdateStudentResponse{
+		Status: "success",
+		Data:   *student,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func DeleteStudentService(c *fiber.Ctx, db *sql.DB) error {
+	id := c.Params("id")
+	if !helper.IsValidUUID(id) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "ID mahasiswa tidak valid.",
+			},
+		})
+	}
+
+	count, err := repository.CountAchievementReferencesByStudentID(db, id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error memeriksa prestasi mahasiswa. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	if count > 0 {
+		return helper.ConflictResponse(c, "Mahasiswa masih memiliki data prestasi dan tidak dapat dihapus.")
+	}
+
+	if err := repository.DeleteStudent(db, id); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Data mahasiswa tidak ditemukan.",
+				},
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error menghapus data mahasiswa. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.DeleteStudentResponse{
+		Status: "success",
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// validateProfileUser memastikan user ada dan role-nya memiliki scope prestasi yang sesuai
+// dengan profil yang akan dibuat. Jika ok bernilai false, response error sudah ditulis.
+func validateProfileUser(c *fiber.Ctx, db *sql.DB, userID string, requiredPermission string) (bool, error) {
+	if !helper.IsValidUUID(userID) {
+		return false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "User ID tidak valid.",
+			},
+		})
+	}
+
+	user, err := repository.GetUserByID(db, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "User tidak ditemukan.",
+				},
+			})
+		}
+		return false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil data user dari database. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	hasPermission, err := utilspostgre.CheckUserPermission(db, user.ID, requiredPermission)
+	if err != nil {
+		return false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Gagal memeriksa permission: " + err.Error(),
+			},
+		})
+	}
+
+	if !hasPermission {
+		return false, c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Role user harus memiliki permission '" + requiredPermission + "' untuk dihubungkan dengan profil ini.",
+			},
+		})
+	}
+
+	return true, nil
+}
+
+func validateAdvisor(c *fiber.Ctx, db *sql.DB, advisorID string) (bool, error) {
+	if advisorID == "" {
+		return true, nil
+	}
+
+	if !helper.IsValidUUID(advisorID) {
+		return false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Advisor ID tidak valid.",
+			},
+		})
+	}
+
+	if _, err := repository.GetLecturerByID(db, advisorID); err != nil {
+		if err == sql.ErrNoRows {
+			return false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Dosen wali tidak ditemukan.",
+				},
+			})
+		}
+		return false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil data dosen wali. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	return true, nil
+}
+
+func studentConflictMessage(constraint string) string {
+	switch constraint {
+	case "students_student_id_key":
+		return "NIM sudah terdaftar."
+	case "students_user_id_key":
+		return "User sudah memiliki profil mahasiswa."
+	default:
+		return "Data mahasiswa sudah ada."
+	}
+}
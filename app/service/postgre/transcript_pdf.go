@@ -0,0 +1,215 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	"strconv"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/skip2/go-qrcode"
+)
+
+type transcriptDocument struct {
+	Student         modelpostgre.TranscriptStudent
+	Transcript      modelpostgre.Transcript
+	VerificationURL string
+	Rows            []transcriptRow
+}
+
+type transcriptRow struct {
+	Title           string
+	Summary         string
+	AchievementType string
+	ParticipantRole string
+	Points          int
+	VerifierName    string
+	VerifiedAt      time.Time
+}
+
+var transcriptAchievementTypes = map[string]string{
+	modelmongo.AchievementTypeAcademic:      "Akademik",
+	modelmongo.AchievementTypeCompetition:   "Kompetisi",
+	modelmongo.AchievementTypeOrganization:  "Organisasi",
+	modelmongo.AchievementTypePublication:   "Publikasi",
+	modelmongo.AchievementTypeCertification: "Sertifikasi",
+	modelmongo.AchievementTypeOther:         "Lainnya",
+}
+
+const (
+	transcriptMargin     = 15.0
+	transcriptLineHeight = 5.0
+	transcriptQRSize     = 32.0
+)
+
+// transcriptColumns adalah lebar kolom tabel prestasi dalam milimeter, totalnya selebar area
+// cetak A4 (210 - 2 x margin).
+var transcriptColumns = []struct {
+	Header string
+	Width  float64
+	Align  string
+}{
+	{"No", 9, "C"},
+	{"Prestasi", 74, "L"},
+	{"Tipe", 22, "L"},
+	{"Poin", 13, "R"},
+	{"Diverifikasi oleh", 36, "L"},
+	{"Tanggal", 26, "L"},
+}
+
+// renderTranscriptPDF menyusun PDF transkrip prestasi dengan font bawaan PDF sehingga tidak
+// membutuhkan file font atau program eksternal. Teks UTF-8 diterjemahkan ke cp1252.
+func renderTranscriptPDF(document transcriptDocument) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(transcriptMargin, transcriptMargin, transcriptMargin)
+	pdf.SetAutoPageBreak(true, transcriptMargin+5)
+	pdf.SetTitle("Transkrip Prestasi "+document.Student.StudentNumber, true)
+	pdf.SetCreator("Sistem Pelaporan Prestasi Mahasiswa", true)
+	pdf.AliasNbPages("")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	code := document.Transcript.VerificationCode
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-transcriptMargin)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.SetTextColor(100, 100, 100)
+		pdf.CellFormat(0, 5, tr("Kode verifikasi: "+code), "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 5, fmt.Sprintf("Halaman %d dari {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	})
+
+	// Header tabel diulang di setiap halaman baru.
+	tableHeader := func() {
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.SetFillColor(230, 230, 230)
+		for _, column := range transcriptColumns {
+			pdf.CellFormat(column.Width, 7, tr(column.Header), "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Helvetica", "", 9)
+	}
+
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(0, 8, "TRANSKRIP PRESTASI MAHASISWA", "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 6, tr("Lampiran Surat Keterangan Pendamping Ijazah (SKPI)"), "", 1, "C", false, 0, "")
+	pdf.Ln(4)
+
+	student := document.Student
+	pdf.SetFont("Helvetica", "", 10)
+	for _, field := range []struct{ Label, Value string }{
+		{"Nama", student.FullName},
+		{"NIM", student.StudentNumber},
+		{"Program Studi", student.ProgramStudy},
+		{"Angkatan", student.AcademicYear},
+		{"Dosen Wali", student.AdvisorName},
+	} {
+		value := field.Value
+		if value == "" {
+			value = "-"
+		}
+		pdf.CellFormat(35, 6, field.Label, "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 6, tr(": "+value), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	tableHeader()
+	if len(document.Rows) == 0 {
+		pdf.CellFormat(0, 8, "Belum ada prestasi yang terverifikasi.", "1", 1, "C", false, 0, "")
+	}
+	for i, row := range document.Rows {
+		prestasi := row.Title
+		if row.Summary != "" {
+			prestasi += "\n" + row.Summary
+		}
+		achievementType := transcriptAchievementTypes[row.AchievementType]
+		if row.ParticipantRole != "" {
+			achievementType += "\n(Tim, " + row.ParticipantRole + ")"
+		}
+		verifiedAt := "-"
+		if !row.VerifiedAt.IsZero() {
+			verifiedAt = formatIndonesianDate(row.VerifiedAt)
+		}
+
+		values := []string{
+			strconv.Itoa(i + 1), prestasi, achievementType, strconv.Itoa(row.Points), row.VerifierName, verifiedAt,
+		}
+		writeTranscriptTableRow(pdf, tr, values, tableHeader)
+	}
+
+	pdf.SetFont("Helvetica", "B", 9)
+	totalLabelWidth := 0.0
+	for _, column := range transcriptColumns[:3] {
+		totalLabelWidth += column.Width
+	}
+	pdf.CellFormat(totalLabelWidth, 7, "Total Poin", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(transcriptColumns[3].Width, 7, strconv.Itoa(document.Transcript.TotalPoints), "1", 0, "R", false, 0, "")
+	pdf.CellFormat(transcriptColumns[4].Width+transcriptColumns[5].Width, 7, "", "1", 1, "L", false, 0, "")
+	pdf.Ln(8)
+
+	// Blok verifikasi tidak dipisah ke dua halaman.
+	_, pageHeight := pdf.GetPageSize()
+	if pdf.GetY()+transcriptQRSize > pageHeight-transcriptMargin-5 {
+		pdf.AddPage()
+	}
+
+	qr, err := qrcode.Encode(document.VerificationURL, qrcode.Medium, 256)
+	if err != nil {
+		return nil, err
+	}
+	options := fpdf.ImageOptions{ImageType: "PNG"}
+	pdf.RegisterImageOptionsReader("verification-qr", options, bytes.NewReader(qr))
+	top := pdf.GetY()
+	pdf.ImageOptions("verification-qr", transcriptMargin, top, transcriptQRSize, transcriptQRSize, false, options, 0, "")
+
+	pdf.SetXY(transcriptMargin+transcriptQRSize+5, top+2)
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(0, 6, "Kode Verifikasi", "", 2, "L", false, 0, "")
+	pdf.SetFont("Courier", "", 10)
+	pdf.CellFormat(0, 6, code, "", 2, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.CellFormat(0, 5, "Diterbitkan "+formatIndonesianDate(document.Transcript.IssuedAt), "", 2, "L", false, 0, "")
+	pdf.MultiCell(0, 5, tr("Dokumen ini dibuat secara elektronik oleh Sistem Pelaporan Prestasi Mahasiswa dan hanya memuat prestasi yang telah diverifikasi. Pindai QR code atau buka "+document.VerificationURL+" untuk memeriksa keaslian dokumen."), "", "L", false)
+
+	var buffer bytes.Buffer
+	if err := pdf.Output(&buffer); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// writeTranscriptTableRow menulis satu baris tabel dengan teks yang dibungkus per kolom. Tinggi
+// baris mengikuti kolom dengan teks terpanjang; jika tidak muat, baris dipindah ke halaman baru.
+func writeTranscriptTableRow(pdf *fpdf.Fpdf, tr func(string) string, values []string, tableHeader func()) {
+	lines := make([][]string, len(values))
+	maxLines := 1
+	for i, value := range values {
+		lines[i] = pdf.SplitText(tr(value), transcriptColumns[i].Width-2)
+		if len(lines[i]) > maxLines {
+			maxLines = len(lines[i])
+		}
+	}
+	height := float64(maxLines)*transcriptLineHeight + 2
+
+	_, pageHeight := pdf.GetPageSize()
+	_, _, _, bottom := pdf.GetMargins()
+	if pdf.GetY()+height > pageHeight-bottom {
+		pdf.AddPage()
+		tableHeader()
+	}
+
+	x, y := pdf.GetXY()
+	for i, column := range transcriptColumns {
+		pdf.Rect(x, y, column.Width, height, "D")
+		for j, line := range lines[i] {
+			pdf.SetXY(x, y+1+float64(j)*transcriptLineHeight)
+			pdf.CellFormat(column.Width, transcriptLineHeight, line, "", 0, column.Align, false, 0, "")
+		}
+		x += column.Width
+	}
+	pdf.SetXY(transcriptMargin, y+height)
+}
@@ -0,0 +1,426 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/helper"
+	utilspostgre "sistem-pelaporan-prestasi-mahasiswa/utils/postgre"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GetStudentTranscriptService membuat PDF transkrip prestasi (SKPI) berisi seluruh prestasi
+// verified milik mahasiswa, termasuk prestasi tim yang diikutinya. Setiap unduhan dicatat di tabel
+// transcripts dan kode verifikasi bertanda tangan dari ID-nya dicetak beserta QR code menuju
+// GET /api/v1/verify/:code. Scope mengikuti aksi baca prestasi: mahasiswa sendiri, dosen wali,
+// atau admin.
+func GetStudentTranscriptService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.Database) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			},
+		})
+	}
+
+	studentID := c.Params("id")
+	if !helper.IsValidUUID(studentID) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "ID mahasiswa tidak valid.",
+			},
+		})
+	}
+
+	policy, ok, err := resolveAchievementPolicy(c, postgresDB, userID, modelpostgre.AchievementActionRead)
+	if !ok {
+		return err
+	}
+
+	if ok, err := authorizeAchievementStudent(c, postgresDB, policy, studentID, "Akses ditolak. Anda hanya dapat mengunduh transkrip prestasi milik sendiri atau mahasiswa bimbingan."); !ok {
+		return err
+	}
+
+	student, err := repositorypostgre.GetTranscriptStudent(postgresDB, studentID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Data mahasiswa tidak ditemukan.",
+				},
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil data mahasiswa dari database. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	entries, err := repositorypostgre.GetTranscriptEntries(postgresDB, studentID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil prestasi terverifikasi. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	mongoIDs := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		mongoIDs = append(mongoIDs, entry.MongoAchievementID)
+	}
+	achievements, err := repositorymongo.GetAchievementsByIDs(mongoDB, mongoIDs)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil achievements dari MongoDB. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	rows, referenceIDs, totalPoints := buildTranscriptRows(entries, achievements)
+
+	transcriptID := uuid.New().String()
+	code, err := utilspostgre.GenerateVerificationCode(modelpostgre.VerificationTypeTranscript, transcriptID)
+	var url string
+	if err == nil {
+		url, err = utilspostgre.VerificationURL(code)
+	}
+	if err != nil {
+		if err == utilspostgre.ErrVerificationSecretMissing || err == utilspostgre.ErrVerificationBaseURLMissing {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Layanan verifikasi belum dikonfigurasi. Detail: " + err.Error(),
+				},
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error membuat kode verifikasi. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	transcript, err := repositorypostgre.CreateTranscript(postgresDB, modelpostgre.Transcript{
+		ID:                      transcriptID,
+		StudentID:               studentID,
+		IssuedBy:                &userID,
+		AchievementReferenceIDs: referenceIDs,
+		TotalPoints:             totalPoints,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mencatat transkrip. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	transcript.VerificationCode = code
+
+	document, err := renderTranscriptPDF(transcriptDocument{
+		Student:         *student,
+		Transcript:      *transcript,
+		VerificationURL: url,
+		Rows:            rows,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error membuat PDF transkrip. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"transcript-%s.pdf\"", student.StudentNumber))
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(fiber.StatusOK).Send(document)
+}
+
+// buildTranscriptRows menggabungkan reference verified dengan dokumen MongoDB. Prestasi tim
+// memakai bagian poin mahasiswa; dokumen yang sudah dihapus tidak dicantumkan.
+func buildTranscriptRows(entries []modelpostgre.TranscriptEntry, achievements []modelmongo.Achievement) ([]transcriptRow, []string, int) {
+	achievementMap := make(map[string]modelmongo.Achievement, len(achievements))
+	for _, achievement := range achievements {
+		achievementMap[achievement.ID.Hex()] = achievement
+	}
+
+	rows := []transcriptRow{}
+	referenceIDs := []string{}
+	totalPoints := 0
+	for _, entry := range entries {
+		achievement, exists := achievementMap[entry.MongoAchievementID]
+		if !exists {
+			continue
+		}
+
+		points := achievement.Points
+		if entry.ParticipantPoints != nil {
+			points = *entry.ParticipantPoints
+		}
+
+		row := transcriptRow{
+			Title:           achievement.Title,
+			Summary:         transcriptAchievementSummary(achievement),
+			AchievementType: achievement.AchievementType,
+			Points:          points,
+			VerifierName:    entry.VerifierName,
+		}
+		if entry.ParticipantRole != nil {
+			row.ParticipantRole = *entry.ParticipantRole
+		}
+		if entry.VerifiedAt != nil {
+			row.VerifiedAt = *entry.VerifiedAt
+		}
+
+		rows = append(rows, row)
+		referenceIDs = append(referenceIDs, entry.ReferenceID)
+		totalPoints += points
+	}
+
+	return rows, referenceIDs, totalPoints
+}
+
+// transcriptAchievementSummary merangkum details terpenting sesuai tipe prestasi dalam satu baris.
+func transcriptAchievementSummary(achievement modelmongo.Achievement) string {
+	details := achievement.Details
+	parts := []string{}
+	add := func(value *string) {
+		if value != nil && *value != "" {
+			parts = append(parts, *value)
+		}
+	}
+
+	switch achievement.AchievementType {
+	case modelmongo.AchievementTypeCompetition:
+		add(details.CompetitionName)
+		if details.CompetitionLevel != nil {
+			level := transcriptCompetitionLevels[*details.CompetitionLevel]
+			add(&level)
+		}
+		if details.Rank != nil {
+			parts = append(parts, fmt.Sprintf("Peringkat %d", *details.Rank))
+		}
+		add(details.MedalType)
+	case modelmongo.AchievementTypePublication:
+		add(details.PublicationTitle)
+		add(details.Publisher)
+	case modelmongo.AchievementTypeOrganization:
+		add(details.OrganizationName)
+		add(details.Position)
+		if details.Period != nil {
+			parts = append(parts, details.Period.Start.Format("2006")+"-"+details.Period.End.Format("2006"))
+		}
+	case modelmongo.AchievementTypeCertification:
+		add(details.CertificationName)
+		add(details.IssuedBy)
+		add(details.CertificationNumber)
+	}
+	if details.EventDate != nil {
+		parts = append(parts, formatIndonesianDate(*details.EventDate))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+var transcriptCompetitionLevels = map[string]string{
+	modelmongo.CompetitionLevelInternational: "Internasional",
+	modelmongo.CompetitionLevelNational:      "Nasional",
+	modelmongo.CompetitionLevelRegional:      "Regional",
+	modelmongo.CompetitionLevelLocal:         "Lokal",
+}
+
+var indonesianMonths = [...]string{
+	"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember",
+}
+
+func formatIndonesianDate(t time.Time) string {
+	return fmt.Sprintf("%d %s %d", t.Day(), indonesianMonths[t.Month()-1], t.Year())
+}
+
+// GetStudentTranscriptsService menampilkan riwayat transkrip yang diterbitkan untuk mahasiswa beserta
+// kode verifikasi dan status pencabutannya. Scope sama seperti unduhan transkrip.
+func GetStudentTranscriptsService(c *fiber.Ctx, postgresDB *sql.DB) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			},
+		})
+	}
+
+	studentID := c.Params("id")
+	if !helper.IsValidUUID(studentID) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "ID mahasiswa tidak valid.",
+			},
+		})
+	}
+
+	policy, ok, err := resolveAchievementPolicy(c, postgresDB, userID, modelpostgre.AchievementActionRead)
+	if !ok {
+		return err
+	}
+
+	if ok, err := authorizeAchievementStudent(c, postgresDB, policy, studentID, "Akses ditolak. Anda hanya dapat melihat transkrip prestasi milik sendiri atau mahasiswa bimbingan."); !ok {
+		return err
+	}
+
+	transcripts, err := repositorypostgre.GetTranscriptsByStudentID(postgresDB, studentID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil data transkrip. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	for i := range transcripts {
+		code, err := utilspostgre.GenerateVerificationCode(modelpostgre.VerificationTypeTranscript, transcripts[i].ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Error membuat kode verifikasi. Detail: " + err.Error(),
+				},
+			})
+		}
+		transcripts[i].VerificationCode = code
+	}
+
+	response := modelpostgre.GetTranscriptsResponse{
+		Status: "success",
+		Data:   transcripts,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// RevokeTranscriptService mencabut transkrip yang sudah diterbitkan, misalnya karena salah cetak
+// atau prestasinya dibatalkan. Setelah dicabut, GET /api/v1/verify/:code mengembalikan status
+// revoked. Hanya untuk user dengan scope verify semua prestasi (admin).
+func RevokeTranscriptService(c *fiber.Ctx, postgresDB *sql.DB) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			},
+		})
+	}
+
+	studentID := c.Params("id")
+	transcriptID := c.Params("transcriptId")
+	if !helper.IsValidUUID(studentID) || !helper.IsValidUUID(transcriptID) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "ID mahasiswa atau ID transkrip tidak valid.",
+			},
+		})
+	}
+
+	policy, ok, err := resolveAchievementPolicy(c, postgresDB, userID, modelpostgre.AchievementActionVerify)
+	if !ok {
+		return err
+	}
+	if policy.Scope != modelpostgre.AchievementScopeAll {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Akses ditolak. Pencabutan transkrip membutuhkan scope '" + modelpostgre.AchievementScopePermission(modelpostgre.AchievementActionVerify, modelpostgre.AchievementScopeAll) + "'.",
+			},
+		})
+	}
+
+	var req modelpostgre.RevokeTranscriptRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Format request body tidak valid. Pastikan JSON format benar. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Alasan pencabutan wajib diisi.",
+			},
+		})
+	}
+
+	transcript, err := repositorypostgre.GetTranscriptByID(postgresDB, transcriptID)
+	if err != nil || transcript.StudentID != studentID {
+		if err == nil || err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Transkrip tidak ditemukan.",
+				},
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil data transkrip. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	revoked, err := repositorypostgre.RevokeTranscript(postgresDB, transcriptID, userID, req.Reason)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Transkrip sudah dicabut.",
+				},
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mencabut transkrip. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := modelpostgre.RevokeTranscriptResponse{
+		Status: "success",
+		Data:   *revoked,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
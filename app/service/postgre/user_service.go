@@ -4,7 +4,9 @@ import (
 	"database/sql"
 	model "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
 	repository "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/helper"
 	utilspostgre "sistem-pelaporan-prestasi-mahasiswa/utils/postgre"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -353,3 +355,412 @@ func HealthCheckService(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
+func GetUsersService(c *fiber.Ctx, db *sql.DB) error {
+	page, limit := helper.ValidatePagination(helper.GetQueryInt(c, "page", 1), helper.GetQueryInt(c, "limit", 10))
+
+	filter := model.UserFilter{
+		Search: helper.SanitizeSearch(c.Query("search")),
+		RoleID: c.Query("role_id"),
+		Limit:  limit,
+		Offset: helper.CalculateOffset(page, limit),
+	}
+
+	if filter.RoleID != "" && !helper.IsValidUUID(filter.RoleID) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Role ID tidak valid.",
+			},
+		})
+	}
+
+	if isActive := c.Query("is_active"); isActive != "" {
+		value := isActive == "true"
+		filter.IsActive = &value
+	}
+
+	users, total, err := repository.GetAllUsers(db, filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil data user dari database. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.GetAllUsersResponse{
+		Status:     "success",
+		Data:       users,
+		Pagination: model.NewPagination(page, limit, total),
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func GetUserByIDService(c *fiber.Ctx, db *sql.DB) error {
+	id := c.Params("id")
+	if !helper.IsValidUUID(id) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "ID user tidak valid.",
+			},
+		})
+	}
+
+	user, err := repository.GetUserByID(db, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Data user tidak ditemukan di database.",
+				},
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil data user dari database. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.GetUserByIDResponse{
+		Status: "success",
+		Data:   *user,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func CreateUserService(c *fiber.Ctx, db *sql.DB) error {
+	var req model.CreateUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Format request body tidak valid. Pastikan JSON format benar. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	req.Username = strings.TrimSpace(req.Username)
+	req.Email = strings.TrimSpace(req.Email)
+	req.FullName = strings.TrimSpace(req.FullName)
+
+	if req.Username == "" || req.Email == "" || req.Password == "" || req.FullName == "" || req.RoleID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Username, email, password, full_name, dan role_id wajib diisi.",
+			},
+		})
+	}
+
+	if len(req.Password) < 8 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Password minimal 8 karakter.",
+			},
+		})
+	}
+
+	if ok, err := validateUserFields(c, db, req.Email, req.RoleID); !ok {
+		return err
+	}
+
+	passwordHash, err := utilspostgre.HashPassword(req.Password)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error hashing password. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	user, err := repository.CreateUser(db, req, passwordHash)
+	if err != nil {
+		if constraint, ok := helper.IsUniqueViolation(err); ok {
+			return helper.ConflictResponse(c, userConflictMessage(constraint))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error menyimpan user ke database. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.CreateUserResponse{
+		Status: "success",
+		Data:   *user,
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+func UpdateUserService(c *fiber.Ctx, db *sql.DB) error {
+	id := c.Params("id")
+	if !helper.IsValidUUID(id) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "ID user tidak valid.",
+			},
+		})
+	}
+
+	var req model.UpdateUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Format request body tidak valid. Pastikan JSON format benar. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	req.Username = strings.TrimSpace(req.Username)
+	req.Email = strings.TrimSpace(req.Email)
+	req.FullName = strings.TrimSpace(req.FullName)
+
+	if req.Username == "" || req.Email == "" || req.FullName == "" || req.RoleID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Username, email, full_name, dan role_id wajib diisi.",
+			},
+		})
+	}
+
+	currentUserID, _ := c.Locals("user_id").(string)
+	if id == currentUserID && req.IsActive != nil && !*req.IsActive {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Anda tidak dapat menonaktifkan akun Anda sendiri.",
+			},
+		})
+	}
+
+	if ok, err := validateUserFields(c, db, req.Email, req.RoleID); !ok {
+		return err
+	}
+
+	user, err := repository.UpdateUser(db, id, req, model.PermissionUserManage)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Data user tidak ditemukan di database.",
+				},
+			})
+		}
+		if err == repository.ErrLastPermissionHolder {
+			return helper.ConflictResponse(c, "User ini adalah satu-satunya user aktif dengan permission '"+model.PermissionUserManage+"'. Role atau status aktifnya tidak dapat diubah.")
+		}
+		if constraint, ok := helper.IsUniqueViolation(err); ok {
+			return helper.ConflictResponse(c, userConflictMessage(constraint))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengupdate user di database. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	if !user.IsActive {
+		if err := repository.DeleteUserRefreshTokens(db, user.ID); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Error mencabut refresh token user. Detail: " + err.Error(),
+				},
+			})
+		}
+	}
+
+	response := model.UpdateUserResponse{
+		Status: "success",
+		Data:   *user,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func DeactivateUserService(c *fiber.Ctx, db *sql.DB) error {
+	id := c.Params("id")
+	if !helper.IsValidUUID(id) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "ID user tidak valid.",
+			},
+		})
+	}
+
+	currentUserID, _ := c.Locals("user_id").(string)
+	if id == currentUserID {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Anda tidak dapat menonaktifkan akun Anda sendiri.",
+			},
+		})
+	}
+
+	if err := repository.UpdateUserActiveStatus(db, id, false, model.PermissionUserManage); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Data user tidak ditemukan di database.",
+				},
+			})
+		}
+		if err == repository.ErrLastPermissionHolder {
+			return helper.ConflictResponse(c, "User ini adalah satu-satunya user aktif dengan permission '"+model.PermissionUserManage+"' dan tidak dapat dinonaktifkan.")
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error menonaktifkan user. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	if err := repository.DeleteUserRefreshTokens(db, id); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mencabut refresh token user. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	user, err := repository.GetUserByID(db, id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil data user yang diupdate. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.UpdateUserResponse{
+		Status: "success",
+		Data:   *user,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func DeleteUserService(c *fiber.Ctx, db *sql.DB) error {
+	id := c.Params("id")
+	if !helper.IsValidUUID(id) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "ID user tidak valid.",
+			},
+		})
+	}
+
+	currentUserID, _ := c.Locals("user_id").(string)
+	if id == currentUserID {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Anda tidak dapat menghapus akun Anda sendiri.",
+			},
+		})
+	}
+
+	if err := repository.DeleteUser(db, id, model.PermissionUserManage); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Data user tidak ditemukan di database.",
+				},
+			})
+		}
+		if err == repository.ErrUserHasProfile {
+			return helper.ConflictResponse(c, "User masih memiliki profil mahasiswa atau dosen. Nonaktifkan user ini, atau hapus profilnya terlebih dahulu.")
+		}
+		if err == repository.ErrLastPermissionHolder {
+			return helper.ConflictResponse(c, "User ini adalah satu-satunya user aktif dengan permission '"+model.PermissionUserManage+"' dan tidak dapat dihapus.")
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error menghapus user. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	response := model.DeleteUserResponse{
+		Status: "success",
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// validateUserFields memeriksa format email dan keberadaan role. Jika ok bernilai false,
+// response error sudah ditulis.
+func validateUserFields(c *fiber.Ctx, db *sql.DB, email string, roleID string) (bool, error) {
+	if !helper.IsValidEmail(email) {
+		return false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Format email tidak valid.",
+			},
+		})
+	}
+
+	if !helper.IsValidUUID(roleID) {
+		return false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Role ID tidak valid.",
+			},
+		})
+	}
+
+	if _, err := repository.GetRoleByID(db, roleID); err != nil {
+		if err == sql.ErrNoRows {
+			return false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Role tidak ditemukan.",
+				},
+			})
+		}
+		return false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error mengambil data role. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	return true, nil
+}
+
+func userConflictMessage(constraint string) string {
+	switch constraint {
+	case "users_username_key":
+		return "Username sudah digunakan."
+	case "users_email_key":
+		return "Email sudah digunakan."
+	default:
+		return "Data user sudah ada."
+	}
+}
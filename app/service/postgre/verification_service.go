@@ -0,0 +1,175 @@
+package service
+
+import (
+	"database/sql"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	utilspostgre "sistem-pelaporan-prestasi-mahasiswa/utils/postgre"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// VerifyCodeService memeriksa kode verifikasi transkrip atau prestasi tanpa autentikasi. Response
+// hanya berisi nama mahasiswa, judul, status, dan tanggal verifikasi prestasi; dokumen yang dicabut
+// hanya mengembalikan status revoked.
+func VerifyCodeService(c *fiber.Ctx, postgresDB *sql.DB, mongoDB *mongo.Database) error {
+	kind, id, err := utilspostgre.ParseVerificationCode(c.Params("code"))
+	if err != nil {
+		if err == utilspostgre.ErrVerificationSecretMissing {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"status": "error",
+				"data": fiber.Map{
+					"message": "Layanan verifikasi belum dikonfigurasi.",
+				},
+			})
+		}
+		return verificationNotFound(c)
+	}
+
+	var summary *modelpostgre.VerificationSummary
+	if kind == modelpostgre.VerificationTypeTranscript {
+		summary, err = verifyTranscript(postgresDB, mongoDB, id)
+	} else {
+		summary, err = verifyAchievement(postgresDB, mongoDB, id)
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return verificationNotFound(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error",
+			"data": fiber.Map{
+				"message": "Error memeriksa kode verifikasi. Detail: " + err.Error(),
+			},
+		})
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	response := modelpostgre.VerifyCodeResponse{
+		Status: "success",
+		Data:   *summary,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func verificationNotFound(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+		"status": "error",
+		"data": fiber.Map{
+			"message": "Kode verifikasi tidak valid atau dokumen tidak ditemukan.",
+		},
+	})
+}
+
+// verifyTranscript mengembalikan ringkasan transkrip beserta status terkini setiap prestasi yang
+// tercantum saat diterbitkan. Prestasi yang sudah tidak verified atau sudah dihapus berstatus revoked.
+func verifyTranscript(postgresDB *sql.DB, mongoDB *mongo.Database, id string) (*modelpostgre.VerificationSummary, error) {
+	transcript, err := repositorypostgre.GetTranscriptByID(postgresDB, id)
+	if err != nil {
+		return nil, err
+	}
+	if transcript.RevokedAt != nil {
+		return &modelpostgre.VerificationSummary{
+			Type:      modelpostgre.VerificationTypeTranscript,
+			Status:    modelpostgre.VerificationStatusRevoked,
+			RevokedAt: transcript.RevokedAt,
+		}, nil
+	}
+
+	student, err := repositorypostgre.GetTranscriptStudent(postgresDB, transcript.StudentID)
+	if err != nil {
+		return nil, err
+	}
+
+	references, err := repositorypostgre.GetAchievementReferencesByIDs(postgresDB, transcript.AchievementReferenceIDs)
+	if err != nil {
+		return nil, err
+	}
+	mongoIDs := make([]string, 0, len(references))
+	referenceMap := make(map[string]modelpostgre.AchievementReference, len(references))
+	for _, ref := range references {
+		mongoIDs = append(mongoIDs, ref.MongoAchievementID)
+		referenceMap[ref.ID] = ref
+	}
+	achievements, err := repositorymongo.GetAchievementsByIDs(mongoDB, mongoIDs)
+	if err != nil {
+		return nil, err
+	}
+	achievementMap := make(map[string]modelmongo.Achievement, len(achievements))
+	for _, achievement := range achievements {
+		achievementMap[achievement.ID.Hex()] = achievement
+	}
+
+	summary := &modelpostgre.VerificationSummary{
+		Type:         modelpostgre.VerificationTypeTranscript,
+		Status:       modelpostgre.VerificationStatusValid,
+		StudentName:  student.FullName,
+		IssuedAt:     &transcript.IssuedAt,
+		Achievements: []modelpostgre.VerificationAchievement{},
+	}
+	// Urutan mengikuti urutan prestasi pada dokumen yang dicetak.
+	for _, referenceID := range transcript.AchievementReferenceIDs {
+		ref, exists := referenceMap[referenceID]
+		if !exists {
+			summary.Achievements = append(summary.Achievements, modelpostgre.VerificationAchievement{
+				Status: modelpostgre.VerificationStatusRevoked,
+			})
+			continue
+		}
+		achievement, exists := achievementMap[ref.MongoAchievementID]
+		summary.Achievements = append(summary.Achievements, newVerificationAchievement(ref, achievement, exists))
+	}
+
+	return summary, nil
+}
+
+// verifyAchievement mengembalikan ringkasan satu prestasi. Prestasi yang tidak lagi verified
+// atau dokumennya sudah dihapus dianggap dicabut.
+func verifyAchievement(postgresDB *sql.DB, mongoDB *mongo.Database, id string) (*modelpostgre.VerificationSummary, error) {
+	ref, err := repositorypostgre.GetAchievementReferenceByID(postgresDB, id)
+	if err != nil {
+		return nil, err
+	}
+
+	achievements, err := repositorymongo.GetAchievementsByIDs(mongoDB, []string{ref.MongoAchievementID})
+	if err != nil {
+		return nil, err
+	}
+	if ref.Status != modelpostgre.AchievementStatusVerified || len(achievements) == 0 {
+		return &modelpostgre.VerificationSummary{
+			Type:   modelpostgre.VerificationTypeAchievement,
+			Status: modelpostgre.VerificationStatusRevoked,
+		}, nil
+	}
+
+	student, err := repositorypostgre.GetTranscriptStudent(postgresDB, ref.StudentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &modelpostgre.VerificationSummary{
+		Type:         modelpostgre.VerificationTypeAchievement,
+		Status:       modelpostgre.VerificationStatusValid,
+		StudentName:  student.FullName,
+		Achievements: []modelpostgre.VerificationAchievement{newVerificationAchievement(*ref, achievements[0], true)},
+	}, nil
+}
+
+// newVerificationAchievement hanya menyertakan judul dan tanggal verifikasi untuk prestasi yang masih
+// verified; prestasi lain hanya berstatus revoked tanpa data apa pun.
+func newVerificationAchievement(ref modelpostgre.AchievementReference, achievement modelmongo.Achievement, exists bool) modelpostgre.VerificationAchievement {
+	if !exists || ref.Status != modelpostgre.AchievementStatusVerified {
+		return modelpostgre.VerificationAchievement{
+			Status: modelpostgre.VerificationStatusRevoked,
+		}
+	}
+	return modelpostgre.VerificationAchievement{
+		Title:      achievement.Title,
+		Status:     ref.Status,
+		VerifiedAt: ref.VerifiedAt,
+	}
+}
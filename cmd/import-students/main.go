@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/config"
+	"sistem-pelaporan-prestasi-mahasiswa/database"
+	"sistem-pelaporan-prestasi-mahasiswa/helper"
+)
+
+func main() {
+	path := flag.String("file", "", "File CSV atau XLSX berisi data mahasiswa (wajib)")
+	dryRun := flag.Bool("dry-run", false, "Hanya validasi dan tampilkan apa yang akan dibuat atau diperbarui")
+	format := flag.String("format", "text", "Format laporan: text, json, atau csv")
+	output := flag.String("output", "", "Tulis laporan ke file ini (default stdout). Laporan berisi password awal.")
+	flag.Parse()
+
+	if *path == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *format != "text" && *format != "json" && *format != "csv" {
+		log.Fatalf("Format tidak valid: %s. Gunakan text, json, atau csv.", *format)
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		log.Fatalf("Failed to open %s: %v", *path, err)
+	}
+	records, err := helper.ReadSpreadsheet(*path, file)
+	file.Close()
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", *path, err)
+	}
+
+	rows, err := modelpostgre.ParseStudentImportRecords(records)
+	if err != nil {
+		log.Fatalf("Invalid import file: %v", err)
+	}
+
+	config.LoadEnv()
+
+	postgresDB := database.ConnectDB()
+	defer postgresDB.Close()
+
+	report, err := servicepostgre.ImportStudents(postgresDB, rows, *dryRun)
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		outputFile, err := os.OpenFile(*output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			log.Fatalf("Failed to create %s: %v", *output, err)
+		}
+		defer outputFile.Close()
+		w = outputFile
+	}
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	case "csv":
+		err = csv.NewWriter(w).WriteAll(report.CSVRecords())
+	default:
+		writeTextReport(w, report)
+	}
+	if err != nil {
+		log.Fatalf("Failed to write report: %v", err)
+	}
+
+	// Exit code 1 menandakan ada baris yang gagal agar dapat dipakai di script.
+	if report.Failed > 0 {
+		postgresDB.Close()
+		os.Exit(1)
+	}
+}
+
+func writeTextReport(w io.Writer, report *modelpostgre.StudentImportReport) {
+	mode := "import"
+	if report.DryRun {
+		mode = "dry-run"
+	}
+
+	fmt.Fprintf(w, "Student import (%s)\n", mode)
+	fmt.Fprintf(w, "Rows: %d, created: %d, updated: %d, unchanged: %d, failed: %d\n\n",
+		report.Total, report.Created, report.Updated, report.Unchanged, report.Failed)
+
+	for _, row := range report.Rows {
+		fmt.Fprintf(w, "- row %d [%s] nim=%s", row.Row, row.Status, row.StudentID)
+		if row.Username != "" {
+			fmt.Fprintf(w, " username=%s", row.Username)
+		}
+		if row.InitialPassword != "" {
+			fmt.Fprintf(w, " password=%s", row.InitialPassword)
+		}
+		fmt.Fprintln(w)
+
+		for _, message := range row.Errors {
+			fmt.Fprintf(w, "    error: %s\n", message)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/config"
+	"sistem-pelaporan-prestasi-mahasiswa/database"
+	"sistem-pelaporan-prestasi-mahasiswa/helper"
+)
+
+func main() {
+	path := flag.String("file", "", "File CSV atau XLSX yang diimport (wajib)")
+	as := flag.String("as", "", "Username atau email admin yang dicatat sebagai pembuat prestasi (wajib)")
+	dryRun := flag.Bool("dry-run", false, "Hanya validasi dan tampilkan apa yang akan dibuat")
+	status := flag.String("status", modelpostgre.AchievementStatusDraft, "Status awal prestasi: draft atau verified")
+	format := flag.String("format", "text", "Format laporan: text atau json")
+	flag.Parse()
+
+	if *path == "" || *as == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *format != "text" && *format != "json" {
+		log.Fatalf("Format tidak valid: %s. Gunakan text atau json.", *format)
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		log.Fatalf("Failed to open %s: %v", *path, err)
+	}
+	records, err := helper.ReadSpreadsheet(*path, file)
+	file.Close()
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", *path, err)
+	}
+
+	rows, err := modelmongo.ParseAchievementImportRecords(records)
+	if err != nil {
+		log.Fatalf("Invalid import file: %v", err)
+	}
+
+	config.LoadEnv()
+
+	postgresDB := database.ConnectDB()
+	defer postgresDB.Close()
+
+	mongoDB := database.ConnectMongoDB()
+
+	user, err := repositorypostgre.GetUserByUsernameOrEmail(postgresDB, *as)
+	if err != nil {
+		log.Fatalf("Failed to find user %s: %v", *as, err)
+	}
+	if err := servicepostgre.CheckAchievementImportPermission(postgresDB, user.ID, *status); err != nil {
+		log.Fatalf("Import not allowed for %s: %v", *as, err)
+	}
+
+	report, err := servicepostgre.ImportAchievements(postgresDB, mongoDB, rows, modelmongo.AchievementImportOptions{
+		DryRun:        *dryRun,
+		InitialStatus: *status,
+		ImportedBy:    user.ID,
+	})
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Fatalf("Failed to write report: %v", err)
+		}
+	} else {
+		writeTextReport(os.Stdout, report)
+	}
+
+	// Exit code 1 menandakan ada baris yang gagal agar dapat dipakai di script.
+	if report.Failed > 0 {
+		postgresDB.Close()
+		os.Exit(1)
+	}
+}
+
+func writeTextReport(w io.Writer, report *modelmongo.AchievementImportReport) {
+	mode := "import"
+	if report.DryRun {
+		mode = "dry-run"
+	}
+
+	fmt.Fprintf(w, "Achievement import (%s), initial status %s\n", mode, report.InitialStatus)
+	fmt.Fprintf(w, "Rows: %d, valid: %d, created: %d, failed: %d\n\n", report.Total, report.Valid, report.Created, report.Failed)
+
+	for _, row := range report.Rows {
+		fmt.Fprintf(w, "- row %d [%s] nim=%s %q", row.Row, row.Status, row.StudentNumber, row.Title)
+		if row.Points != nil {
+			fmt.Fprintf(w, " points=%d", *row.Points)
+		}
+		if row.AchievementID != "" {
+			fmt.Fprintf(w, " id=%s", row.AchievementID)
+		}
+		fmt.Fprintln(w)
+
+		for _, fieldError := range row.Errors {
+			fmt.Fprintf(w, "    error %s: %s\n", fieldError.Field, fieldError.Message)
+		}
+		for _, duplicate := range row.Duplicates {
+			fmt.Fprintf(w, "    possible duplicate of %s (score %.2f, %v)\n", duplicate.AchievementID, duplicate.Score, duplicate.Reasons)
+		}
+	}
+}
@@ -1,23 +1,150 @@
 package main
 
 import (
+	"database/sql"
+	"fmt"
 	"log"
+	"os"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
 	"sistem-pelaporan-prestasi-mahasiswa/config"
 	"sistem-pelaporan-prestasi-mahasiswa/database"
+	"strconv"
+	"time"
 )
 
+const usage = `Usage: go run cmd/migrate/main.go <command> [argument]
+
+Commands:
+  up                 Jalankan semua migration yang belum dijalankan dan pasang validator serta index MongoDB
+  down [N]           Batalkan N migration terakhir (default 1)
+  status             Tampilkan status setiap migration
+  baseline <VERSION> Tandai migration sampai VERSION sebagai sudah dijalankan tanpa mengeksekusinya
+  environment [NAME] Tampilkan atau ubah penanda environment database (development, staging, production)
+  check-mongo        Tampilkan dokumen achievements yang tidak sesuai dengan validator MongoDB`
+
 func main() {
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+
+	command := os.Args[1]
+	argument := ""
+	if len(os.Args) > 2 {
+		argument = os.Args[2]
+	}
+
 	config.LoadEnv()
 
 	postgresDB := database.ConnectDB()
 	defer postgresDB.Close()
 
-	mongoDB := database.ConnectMongoDB()
+	switch command {
+	case "up":
+		mongoDB := database.ConnectMongoDB()
+		applied, err := database.MigrateUp(postgresDB, mongoDB)
+		if err != nil {
+			log.Fatalf("Migration failed after applying %d migration(s): %v", applied, err)
+		}
+		log.Printf("Migration completed successfully, %d migration(s) applied", applied)
+	case "down":
+		steps := 1
+		if argument != "" {
+			parsed, err := strconv.Atoi(argument)
+			if err != nil || parsed <= 0 {
+				log.Fatalf("Invalid number of steps: %s", argument)
+			}
+			steps = parsed
+		}
+		reverted, err := database.MigrateDown(postgresDB, steps)
+		if err != nil {
+			log.Fatalf("Rollback failed after reverting %d migration(s): %v", reverted, err)
+		}
+		log.Printf("Rollback completed successfully, %d migration(s) reverted", reverted)
+	case "status":
+		statuses, err := database.GetMigrationStatus(postgresDB)
+		if err != nil {
+			log.Fatalf("Failed to read migration status: %v", err)
+		}
+		printStatus(statuses)
+	case "baseline":
+		version, err := strconv.Atoi(argument)
+		if err != nil || version <= 0 {
+			log.Fatalf("Baseline requires a migration version, e.g. `baseline 2`")
+		}
+		marked, err := database.BaselineMigrations(postgresDB, version)
+		if err != nil {
+			log.Fatalf("Baseline failed: %v", err)
+		}
+		log.Printf("Baseline completed successfully, %d migration(s) marked as applied", marked)
+	case "environment":
+		if argument == "" {
+			environment, err := repositorypostgre.GetAppSetting(postgresDB, modelpostgre.AppSettingEnvironment)
+			if err == sql.ErrNoRows {
+				fmt.Println("(not set)")
+				return
+			}
+			if err != nil {
+				log.Fatalf("Failed to read database environment: %v", err)
+			}
+			fmt.Println(environment)
+			return
+		}
+		if !modelpostgre.IsValidEnvironment(argument) {
+			log.Fatalf("Invalid environment: %s. Use development, staging, or production.", argument)
+		}
+		if err := repositorypostgre.SetAppSetting(postgresDB, modelpostgre.AppSettingEnvironment, argument); err != nil {
+			log.Fatalf("Failed to set database environment: %v", err)
+		}
+		log.Printf("Database environment set to %s", argument)
+	case "check-mongo":
+		mongoDB := database.ConnectMongoDB()
+		invalid, err := database.FindInvalidAchievements(mongoDB)
+		if err != nil {
+			log.Fatalf("Failed to check achievements collection: %v", err)
+		}
+		printInvalidAchievements(invalid)
+		if len(invalid) > 0 {
+			postgresDB.Close()
+			os.Exit(1)
+		}
+	default:
+		fmt.Println(usage)
+		postgresDB.Close()
+		os.Exit(2)
+	}
+}
+
+func printStatus(statuses []database.MigrationStatus) {
+	fmt.Printf("%-8s %-40s %-10s %s\n", "VERSION", "NAME", "STATUS", "APPLIED AT")
+	for _, status := range statuses {
+		state := "pending"
+		appliedAt := "-"
+		if status.Applied {
+			state = "applied"
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Printf("%04d     %-40s %-10s %s\n", status.Version, status.Name, state, appliedAt)
+	}
+}
 
-	if err := database.RunMigrations(postgresDB, mongoDB); err != nil {
-		log.Fatalf("Migration failed: %v", err)
+func printInvalidAchievements(invalid []database.InvalidAchievement) {
+	if len(invalid) == 0 {
+		fmt.Println("All achievements documents match the validator.")
+		return
 	}
 
-	log.Println("Migration completed successfully")
+	fmt.Printf("%-26s %-38s %-15s %s\n", "ID", "STUDENT ID", "TYPE", "TITLE")
+	for _, document := range invalid {
+		fmt.Printf("%-26s %-38v %-15v %v\n", document.ID.Hex(), orDash(document.StudentID), orDash(document.AchievementType), orDash(document.Title))
+	}
+	fmt.Printf("\n%d document(s) violate the achievements validator\n", len(invalid))
 }
 
+func orDash(value interface{}) interface{} {
+	if value == nil {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/config"
+	"sistem-pelaporan-prestasi-mahasiswa/database"
+	"time"
+)
+
+func main() {
+	format := flag.String("format", "text", "Format laporan: text atau json")
+	fix := flag.Bool("fix", false, "Terapkan perbaikan aman (default hanya laporan / dry-run)")
+	grace := flag.Duration("grace", 10*time.Minute, "Lewati dokumen yang dibuat kurang dari durasi ini karena mungkin masih diproses")
+	flag.Parse()
+
+	if *format != "text" && *format != "json" {
+		log.Fatalf("Format tidak valid: %s. Gunakan text atau json.", *format)
+	}
+
+	config.LoadEnv()
+
+	postgresDB := database.ConnectDB()
+	defer postgresDB.Close()
+
+	mongoDB := database.ConnectMongoDB()
+
+	report, err := servicepostgre.ReconcileAchievements(postgresDB, mongoDB, *fix, *grace)
+	if err != nil {
+		log.Fatalf("Reconciliation failed: %v", err)
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Fatalf("Failed to write report: %v", err)
+		}
+	} else {
+		writeTextReport(os.Stdout, report)
+	}
+
+	// Exit code 1 menandakan masih ada masalah yang belum diperbaiki, sehingga perintah ini
+	// dapat dipakai di cron atau CI.
+	if report.Unresolved() > 0 {
+		postgresDB.Close()
+		os.Exit(1)
+	}
+}
+
+func writeTextReport(w io.Writer, report *modelpostgre.ReconcileReport) {
+	mode := "fix"
+	if report.DryRun {
+		mode = "dry-run"
+	}
+
+	fmt.Fprintf(w, "Achievement reconciliation (%s) - %s\n", mode, report.GeneratedAt.Format(time.RFC3339))
+	fmt.Fprintf(w, "Scanned %d MongoDB documents and %d PostgreSQL references, skipped %d in-flight\n\n",
+		report.ScannedDocuments, report.ScannedReferences, report.SkippedInFlight)
+
+	fmt.Fprintln(w, "Summary:")
+	for _, issueType := range modelpostgre.ReconcileIssueTypes {
+		fmt.Fprintf(w, "  %-36s %d\n", issueType, report.Summary[issueType])
+	}
+
+	if len(report.Issues) == 0 {
+		fmt.Fprintln(w, "\nNo inconsistencies found.")
+		return
+	}
+
+	fmt.Fprintln(w, "\nIssues:")
+	for _, issue := range report.Issues {
+		fmt.Fprintf(w, "- [%s] mongo=%s", issue.Type, issue.MongoAchievementID)
+		if issue.AchievementReferenceID != nil {
+			fmt.Fprintf(w, " reference=%s status=%s", *issue.AchievementReferenceID, issue.ReferenceStatus)
+		}
+		if issue.MongoStudentID != "" {
+			fmt.Fprintf(w, " mongo_student=%s", issue.MongoStudentID)
+		}
+		if issue.PostgresStudentID != "" {
+			fmt.Fprintf(w, " postgres_student=%s", issue.PostgresStudentID)
+		}
+		fmt.Fprintln(w)
+
+		switch {
+		case issue.Fixed:
+			fmt.Fprintf(w, "    fixed: %s\n", issue.Repair)
+		case issue.FixError != "":
+			fmt.Fprintf(w, "    fix failed: %s (%s)\n", issue.Repair, issue.FixError)
+		default:
+			fmt.Fprintf(w, "    repair: %s\n", issue.Repair)
+		}
+	}
+
+	fmt.Fprintf(w, "\nUnresolved: %d\n", report.Unresolved())
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/config"
+	"sistem-pelaporan-prestasi-mahasiswa/database"
+	"strings"
+)
+
+func main() {
+	fixture := flag.String("fixture", modelpostgre.SeedFixtureDemo, "Fixture: "+strings.Join(modelpostgre.SeedFixtures, ", "))
+	password := flag.String("password", "", "Password untuk akun yang dibuat (default: 12345678 untuk demo, acak untuk minimal dan load-test)")
+	lecturers := flag.Int("lecturers", 10, "Jumlah dosen wali (load-test)")
+	students := flag.Int("students", 100, "Jumlah mahasiswa (load-test)")
+	achievements := flag.Int("achievements", 5, "Jumlah prestasi per mahasiswa (load-test)")
+	randomSeed := flag.Int64("random-seed", 1, "Seed generator acak agar data load-test dapat direproduksi")
+	flag.Parse()
+
+	config.LoadEnv()
+
+	postgresDB := database.ConnectDB()
+	defer postgresDB.Close()
+
+	mongoDB := database.ConnectMongoDB()
+
+	opts := modelpostgre.SeedOptions{
+		Password:               *password,
+		Lecturers:              *lecturers,
+		Students:               *students,
+		AchievementsPerStudent: *achievements,
+		RandomSeed:             *randomSeed,
+	}
+
+	result, err := servicepostgre.SeedFixture(postgresDB, mongoDB, *fixture, opts)
+	if result != nil {
+		fmt.Printf("Fixture %s: %d user(s), %d lecturer(s), %d student(s), %d achievement(s) created\n",
+			result.Fixture, result.Users, result.Lecturers, result.Students, result.Achievements)
+		for _, credential := range result.Credentials {
+			fmt.Printf("  login: %s / %s\n", credential.Username, credential.Password)
+		}
+	}
+	if err != nil {
+		postgresDB.Close()
+		log.Printf("Seeding failed: %v", err)
+		os.Exit(1)
+	}
+}
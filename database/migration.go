@@ -3,8 +3,14 @@ package database
 import (
 	"context"
 	"database/sql"
+	"embed"
 	"fmt"
+	"io/fs"
 	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -12,307 +18,378 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-const postgresSchemaSQL = `DROP EXTENSION IF EXISTS "uuid-ossp" CASCADE;
-
-DROP TABLE IF EXISTS refresh_tokens CASCADE;
-DROP TABLE IF EXISTS achievement_references CASCADE;
-DROP TABLE IF EXISTS students CASCADE;
-DROP TABLE IF EXISTS lecturers CASCADE;
-DROP TABLE IF EXISTS role_permissions CASCADE;
-DROP TABLE IF EXISTS users CASCADE;
-DROP TABLE IF EXISTS permissions CASCADE;
-DROP TABLE IF EXISTS roles CASCADE;
-
-DROP TYPE IF EXISTS achievement_status CASCADE;
-
-DROP FUNCTION IF EXISTS update_updated_at_column() CASCADE;
-
-CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
-
-CREATE TYPE achievement_status AS ENUM ('draft', 'submitted', 'verified', 'rejected', 'deleted');
-
-CREATE TABLE roles (
-    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    name VARCHAR(50) UNIQUE NOT NULL,
-    description TEXT,
-    created_at TIMESTAMP DEFAULT NOW()
-);
-
-CREATE TABLE users (
-    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    username VARCHAR(50) UNIQUE NOT NULL,
-    email VARCHAR(100) UNIQUE NOT NULL,
-    password_hash VARCHAR(255) NOT NULL,
-    full_name VARCHAR(100) NOT NULL,
-    role_id UUID NOT NULL REFERENCES roles(id) ON DELETE RESTRICT,
-    is_active BOOLEAN DEFAULT true,
-    created_at TIMESTAMP DEFAULT NOW(),
-    updated_at TIMESTAMP DEFAULT NOW()
-);
-
-CREATE TABLE permissions (
-    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    name VARCHAR(100) UNIQUE NOT NULL,
-    resource VARCHAR(50) NOT NULL,
-    action VARCHAR(50) NOT NULL,
-    description TEXT
-);
-
-CREATE TABLE role_permissions (
-    role_id UUID NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
-    permission_id UUID NOT NULL REFERENCES permissions(id) ON DELETE CASCADE,
-    PRIMARY KEY (role_id, permission_id)
-);
-
-CREATE TABLE lecturers (
-    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    user_id UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
-    lecturer_id VARCHAR(20) UNIQUE NOT NULL,
-    department VARCHAR(100),
-    created_at TIMESTAMP DEFAULT NOW()
-);
-
-CREATE TABLE students (
-    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    user_id UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
-    student_id VARCHAR(20) UNIQUE NOT NULL,
-    program_study VARCHAR(100),
-    academic_year VARCHAR(10),
-    advisor_id UUID REFERENCES lecturers(id) ON DELETE SET NULL,
-    created_at TIMESTAMP DEFAULT NOW()
-);
-
-CREATE TABLE achievement_references (
-    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
-    mongo_achievement_id VARCHAR(24) NOT NULL,
-    status achievement_status NOT NULL DEFAULT 'draft',
-    submitted_at TIMESTAMP,
-    verified_at TIMESTAMP,
-    verified_by UUID REFERENCES users(id) ON DELETE SET NULL,
-    rejection_note TEXT,
-    created_at TIMESTAMP DEFAULT NOW(),
-    updated_at TIMESTAMP DEFAULT NOW()
-);
-
-CREATE INDEX idx_users_role_id ON users(role_id);
-CREATE INDEX idx_users_email ON users(email);
-CREATE INDEX idx_users_username ON users(username);
-CREATE INDEX idx_role_permissions_role_id ON role_permissions(role_id);
-CREATE INDEX idx_role_permissions_permission_id ON role_permissions(permission_id);
-CREATE INDEX idx_students_user_id ON students(user_id);
-CREATE INDEX idx_students_advisor_id ON students(advisor_id);
-CREATE INDEX idx_lecturers_user_id ON lecturers(user_id);
-CREATE INDEX idx_achievement_references_student_id ON achievement_references(student_id);
-CREATE INDEX idx_achievement_references_status ON achievement_references(status);
-CREATE INDEX idx_achievement_references_verified_by ON achievement_references(verified_by);
-
-CREATE TABLE refresh_tokens (
-    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-    token TEXT UNIQUE NOT NULL,
-    expires_at TIMESTAMP NOT NULL,
-    created_at TIMESTAMP DEFAULT NOW()
-);
-
-CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens(user_id);
-CREATE INDEX idx_refresh_tokens_token ON refresh_tokens(token);
-CREATE INDEX idx_refresh_tokens_expires_at ON refresh_tokens(expires_at);
-
-CREATE OR REPLACE FUNCTION update_updated_at_column()
-RETURNS TRIGGER AS $$
-BEGIN
-    NEW.updated_at = NOW();
-    RETURN NEW;
-END;
-$$ language 'plpgsql';
-
-CREATE TRIGGER update_users_updated_at BEFORE UPDATE ON users
-    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
-
-CREATE TRIGGER update_achievement_references_updated_at BEFORE UPDATE ON achievement_references
-    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();`
-
-const postgresSampleDataSQL = `-- Sample Data untuk PostgreSQL
--- Jalankan file ini setelah menjalankan postgre_schema.sql
-
--- Hapus data yang sudah ada (jika ada)
-DELETE FROM students;
-DELETE FROM lecturers;
-DELETE FROM role_permissions;
-DELETE FROM users;
-DELETE FROM permissions;
-DELETE FROM roles;
-
--- Insert Roles
-INSERT INTO roles (name, description) VALUES
-('Admin', 'Pengelola sistem dengan akses penuh'),
-('Mahasiswa', 'Pelapor prestasi'),
-('Dosen Wali', 'Verifikator prestasi mahasiswa bimbingannya');
-
--- Insert Permissions
-INSERT INTO permissions (name, resource, action, description) VALUES
-('achievement:create', 'achievement', 'create', 'Membuat prestasi baru'),
-('achievement:read', 'achievement', 'read', 'Membaca data prestasi'),
-('achievement:update', 'achievement', 'update', 'Mengupdate data prestasi'),
-('achievement:delete', 'achievement', 'delete', 'Menghapus data prestasi'),
-('achievement:verify', 'achievement', 'verify', 'Memverifikasi prestasi'),
-('user:manage', 'user', 'manage', 'Mengelola pengguna');
-
--- Insert Role Permissions
-INSERT INTO role_permissions (role_id, permission_id)
-SELECT r.id, p.id
-FROM roles r
-CROSS JOIN permissions p
-WHERE (r.name = 'Admin' AND p.name IN (
-    'achievement:create', 'achievement:read', 'achievement:update', 
-    'achievement:delete', 'achievement:verify', 'user:manage'
-))
-OR (r.name = 'Mahasiswa' AND p.name IN (
-    'achievement:create', 'achievement:read', 'achievement:update', 'achievement:delete'
-))
-OR (r.name = 'Dosen Wali' AND p.name IN (
-    'achievement:read', 'achievement:verify'
-));
-
--- Insert Users (Total 7: 1 Admin, 3 Dosen Wali, 3 Mahasiswa)
--- Password untuk semua: 12345678
-
--- User Admin (1)
-INSERT INTO users (username, email, password_hash, full_name, role_id, is_active)
-SELECT 
-    'admin',
-    'admin@gmail.com',
-    '$2a$12$iix7znEDxwTFySv47.9.2u6Uh3LYNBh/TcNRbBfqK0Sg24wWmdyja',
-    'Administrator',
-    r.id,
-    true
-FROM roles r
-WHERE r.name = 'Admin'
-LIMIT 1;
-
--- Users Dosen Wali (3)
-INSERT INTO users (username, email, password_hash, full_name, role_id, is_active) VALUES
-('dosen1', 'dosen1@gmail.com', '$2a$12$iix7znEDxwTFySv47.9.2u6Uh3LYNBh/TcNRbBfqK0Sg24wWmdyja', 'Prof. Dr. Ahmad Wijaya, S.T., M.T.', (SELECT id FROM roles WHERE name = 'Dosen Wali'), true),
-('dosen2', 'dosen2@gmail.com', '$2a$12$iix7znEDxwTFySv47.9.2u6Uh3LYNBh/TcNRbBfqK0Sg24wWmdyja', 'Dr. Siti Nurhaliza, S.Kom., M.Kom.', (SELECT id FROM roles WHERE name = 'Dosen Wali'), true),
-('dosen3', 'dosen3@gmail.com', '$2a$12$iix7znEDxwTFySv47.9.2u6Uh3LYNBh/TcNRbBfqK0Sg24wWmdyja', 'Dr. Budi Santoso, S.T., M.Sc.', (SELECT id FROM roles WHERE name = 'Dosen Wali'), true);
-
--- Users Mahasiswa (3)
-INSERT INTO users (username, email, password_hash, full_name, role_id, is_active) VALUES
-('mahasiswa1', 'mahasiswa1@gmail.com', '$2a$12$iix7znEDxwTFySv47.9.2u6Uh3LYNBh/TcNRbBfqK0Sg24wWmdyja', 'Andi Pratama', (SELECT id FROM roles WHERE name = 'Mahasiswa'), true),
-('mahasiswa2', 'mahasiswa2@gmail.com', '$2a$12$iix7znEDxwTFySv47.9.2u6Uh3LYNBh/TcNRbBfqK0Sg24wWmdyja', 'Budi Setiawan', (SELECT id FROM roles WHERE name = 'Mahasiswa'), true),
-('mahasiswa3', 'mahasiswa3@gmail.com', '$2a$12$iix7znEDxwTFySv47.9.2u6Uh3LYNBh/TcNRbBfqK0Sg24wWmdyja', 'Citra Dewi', (SELECT id FROM roles WHERE name = 'Mahasiswa'), true);
-
--- Insert Lecturers (3 data untuk 3 dosen wali)
-INSERT INTO lecturers (user_id, lecturer_id, department)
-SELECT 
-    u.id,
-    CASE u.username
-        WHEN 'dosen1' THEN 'DOS001'
-        WHEN 'dosen2' THEN 'DOS002'
-        WHEN 'dosen3' THEN 'DOS003'
-    END,
-    'Teknik Informatika'
-FROM users u
-WHERE u.username IN ('dosen1', 'dosen2', 'dosen3');
-
--- Insert Students (3 data untuk 3 mahasiswa)
-INSERT INTO students (user_id, student_id, program_study, academic_year, advisor_id)
-SELECT 
-    u.id,
-    CASE u.username
-        WHEN 'mahasiswa1' THEN '202410001'
-        WHEN 'mahasiswa2' THEN '202410002'
-        WHEN 'mahasiswa3' THEN '202410003'
-    END,
-    'Teknik Informatika',
-    '2024',
-    CASE u.username
-        WHEN 'mahasiswa1' THEN (SELECT l.id FROM lecturers l JOIN users u2 ON l.user_id = u2.id WHERE u2.username = 'dosen1' LIMIT 1)
-        WHEN 'mahasiswa2' THEN (SELECT l.id FROM lecturers l JOIN users u2 ON l.user_id = u2.id WHERE u2.username = 'dosen2' LIMIT 1)
-        WHEN 'mahasiswa3' THEN (SELECT l.id FROM lecturers l JOIN users u2 ON l.user_id = u2.id WHERE u2.username = 'dosen3' LIMIT 1)
-    END
-FROM users u
-WHERE u.username IN ('mahasiswa1', 'mahasiswa2', 'mahasiswa3');`
-
-// RunMigrations menjalankan migrasi PostgreSQL dan MongoDB secara berurutan.
-func RunMigrations(postgresDB *sql.DB, mongoDB *mongo.Database) error {
-	log.Println("Starting database migrations...")
-
-	if err := runPostgresMigrations(postgresDB); err != nil {
-		return fmt.Errorf("postgres migrations failed: %w", err)
+// Migration PostgreSQL disimpan sebagai pasangan file migrations/NNNN_nama.up.sql dan
+// migrations/NNNN_nama.down.sql. Versi yang sudah dijalankan dicatat di tabel schema_migrations.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockID adalah kunci advisory lock PostgreSQL agar dua proses tidak menjalankan
+// migration secara bersamaan.
+const migrationLockID = 7305142001
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// LoadMigrations membaca seluruh migration dan mengurutkannya berdasarkan versi.
+func LoadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
 	}
 
-	if err := runMongoMigrations(mongoDB); err != nil {
-		return fmt.Errorf("mongo migrations failed: %w", err)
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		fileName := entry.Name()
+
+		var direction string
+		switch {
+		case strings.HasSuffix(fileName, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(fileName, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("migration file %s must end with .up.sql or .down.sql", fileName)
+		}
+
+		base := strings.TrimSuffix(fileName, "."+direction+".sql")
+		versionPart, name, found := strings.Cut(base, "_")
+		if !found {
+			return nil, fmt.Errorf("migration file %s must be named NNNN_name.%s.sql", fileName, direction)
+		}
+
+		version, err := strconv.Atoi(versionPart)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration file %s has an invalid version", fileName)
+		}
+
+		content, err := migrationFiles.ReadFile(path.Join("migrations", fileName))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		} else if migration.Name != name {
+			return nil, fmt.Errorf("migration version %d is used by %s and %s", version, migration.Name, name)
+		}
+
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
 	}
 
-	log.Println("Database migrations completed successfully")
-	return nil
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s must have both up and down files", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
 }
 
-func runPostgresMigrations(db *sql.DB) error {
-	log.Println("Running PostgreSQL schema and seed migrations...")
+// MigrateUp menjalankan seluruh migration PostgreSQL yang belum dijalankan, lalu memastikan
+// validator dan index MongoDB terpasang. Data yang sudah ada tidak dihapus. Mengembalikan jumlah migration
+// PostgreSQL yang dijalankan.
+func MigrateUp(postgresDB *sql.DB, mongoDB *mongo.Database) (int, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return 0, err
+	}
 
-	tx, err := db.Begin()
+	applied := 0
+	err = withMigrationLock(postgresDB, func(conn *sql.Conn) error {
+		appliedVersions, err := getAppliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+
+		if len(appliedVersions) == 0 {
+			if err := checkLegacySchema(conn); err != nil {
+				return err
+			}
+		}
+
+		for _, migration := range migrations {
+			if _, exists := appliedVersions[migration.Version]; exists {
+				continue
+			}
+
+			log.Printf("Applying migration %04d_%s", migration.Version, migration.Name)
+			err := runInMigrationTx(conn, func(tx *sql.Tx) error {
+				if _, err := tx.Exec(migration.Up); err != nil {
+					return err
+				}
+				_, err := tx.Exec(`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, migration.Version, migration.Name)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+			}
+			applied++
+		}
+
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("begin transaction: %w", err)
+		return applied, err
 	}
 
-	if _, err := tx.Exec(postgresSchemaSQL); err != nil {
-		tx.Rollback()
-		return fmt.Errorf("executing schema SQL: %w", err)
+	if err := ensureMongoSchema(mongoDB); err != nil {
+		return applied, fmt.Errorf("mongo schema: %w", err)
+	}
+
+	return applied, nil
+}
+
+// MigrateDown membatalkan steps migration terakhir secara berurutan dari versi tertinggi.
+// Mengembalikan jumlah migration yang dibatalkan.
+func MigrateDown(postgresDB *sql.DB, steps int) (int, error) {
+	if steps <= 0 {
+		return 0, fmt.Errorf("steps must be greater than 0")
 	}
 
-	if _, err := tx.Exec(postgresSampleDataSQL); err != nil {
-		tx.Rollback()
-		return fmt.Errorf("executing sample data SQL: %w", err)
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return 0, err
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit transaction: %w", err)
+	byVersion := make(map[int]Migration, len(migrations))
+	for _, migration := range migrations {
+		byVersion[migration.Version] = migration
 	}
 
-	log.Println("PostgreSQL migrations completed")
-	return nil
+	reverted := 0
+	err = withMigrationLock(postgresDB, func(conn *sql.Conn) error {
+		appliedVersions, err := getAppliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+
+		versions := make([]int, 0, len(appliedVersions))
+		for version := range appliedVersions {
+			versions = append(versions, version)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+
+		for _, version := range versions {
+			if reverted == steps {
+				break
+			}
+
+			migration, exists := byVersion[version]
+			if !exists {
+				return fmt.Errorf("migration %04d is applied but its files are missing", version)
+			}
+
+			log.Printf("Reverting migration %04d_%s", migration.Version, migration.Name)
+			err := runInMigrationTx(conn, func(tx *sql.Tx) error {
+				if _, err := tx.Exec(migration.Down); err != nil {
+					return err
+				}
+				_, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("revert migration %04d_%s: %w", migration.Version, migration.Name, err)
+			}
+			reverted++
+		}
+
+		return nil
+	})
+
+	return reverted, err
 }
 
-func runMongoMigrations(db *mongo.Database) error {
-	log.Println("Running MongoDB migrations...")
+// GetMigrationStatus mengembalikan status seluruh migration yang dikenal beserta waktu dijalankan.
+func GetMigrationStatus(postgresDB *sql.DB) ([]MigrationStatus, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	conn, err := postgresDB.Conn(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
 
-	if err := dropCollectionIfExists(ctx, db, "achievements"); err != nil {
+	appliedVersions, err := getAppliedMigrations(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if appliedAt, exists := appliedVersions[migration.Version]; exists {
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// BaselineMigrations menandai migration sampai version sebagai sudah dijalankan tanpa
+// mengeksekusinya. Dipakai untuk database yang dibuat dengan migration lama yang destruktif.
+func BaselineMigrations(postgresDB *sql.DB, version int) (int, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return 0, err
+	}
+
+	marked := 0
+	err = withMigrationLock(postgresDB, func(conn *sql.Conn) error {
+		appliedVersions, err := getAppliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+
+		return runInMigrationTx(conn, func(tx *sql.Tx) error {
+			for _, migration := range migrations {
+				if migration.Version > version {
+					break
+				}
+				if _, exists := appliedVersions[migration.Version]; exists {
+					continue
+				}
+				if _, err := tx.Exec(`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, migration.Version, migration.Name); err != nil {
+					return err
+				}
+				marked++
+			}
+			return nil
+		})
+	})
+
+	return marked, err
+}
+
+func withMigrationLock(postgresDB *sql.DB, fn func(conn *sql.Conn) error) error {
+	ctx := context.Background()
+	conn, err := postgresDB.Conn(ctx)
+	if err != nil {
 		return err
 	}
+	defer conn.Close()
 
-	if err := createAchievementIndexes(ctx, db); err != nil {
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, migrationLockID)
+
+	return fn(conn)
+}
+
+func runInMigrationTx(conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(context.Background(), nil)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	log.Println("MongoDB migrations completed")
-	return nil
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func dropCollectionIfExists(ctx context.Context, db *mongo.Database, collectionName string) error {
-	names, err := db.ListCollectionNames(ctx, bson.M{"name": collectionName})
+func getAppliedMigrations(conn *sql.Conn) (map[int]time.Time, error) {
+	ctx := context.Background()
+	query := `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)
+	`
+	if _, err := conn.ExecContext(ctx, query); err != nil {
+		return nil, fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	rows, err := conn.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
 	if err != nil {
-		return fmt.Errorf("list collections for %s: %w", collectionName, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
 	}
 
-	if len(names) == 0 {
-		return nil
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+// checkLegacySchema menolak menjalankan migration pada database yang dibuat dengan migration
+// lama (tabel sudah ada tetapi schema_migrations masih kosong).
+func checkLegacySchema(conn *sql.Conn) error {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM information_schema.tables WHERE table_schema = 'public' AND table_name = 'users')`
+	if err := conn.QueryRowContext(context.Background(), query).Scan(&exists); err != nil {
+		return err
 	}
 
-	if err := db.Collection(collectionName).Drop(ctx); err != nil {
-		return fmt.Errorf("drop collection %s: %w", collectionName, err)
+	if exists {
+		return fmt.Errorf("database already contains tables but schema_migrations is empty; " +
+			"mark the existing schema with `migrate baseline <version>` before running `migrate up`")
 	}
 
-	log.Printf("Dropped collection: %s", collectionName)
 	return nil
 }
 
+// ensureMongoSchema memasang validator dan index koleksi achievements serta index koleksi
+// achievement_revisions. Aman dijalankan berulang kali karena validator selalu ditimpa dengan
+// definisi terbaru dan index dengan nama dan definisi yang sama tidak dibuat ulang.
+func ensureMongoSchema(db *mongo.Database) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := ensureAchievementValidator(ctx, db); err != nil {
+		return err
+	}
+
+	if err := createAchievementIndexes(ctx, db); err != nil {
+		return err
+	}
+
+	return createAchievementRevisionIndexes(ctx, db)
+}
+
 func createAchievementIndexes(ctx context.Context, db *mongo.Database) error {
 	collection := db.Collection("achievements")
 
@@ -336,6 +413,14 @@ func createAchievementIndexes(ctx context.Context, db *mongo.Database) error {
 			},
 			Options: options.Index().SetName("idx_text_search"),
 		},
+		{
+			Keys:    bson.D{{Key: "attachments.checksum", Value: 1}},
+			Options: options.Index().SetName("idx_attachment_checksum").SetSparse(true),
+		},
+		{
+			Keys:    bson.D{{Key: "details.certificationNumber", Value: 1}},
+			Options: options.Index().SetName("idx_certification_number").SetSparse(true),
+		},
 	}
 
 	if _, err := collection.Indexes().CreateMany(ctx, indexModels); err != nil {
@@ -345,3 +430,17 @@ func createAchievementIndexes(ctx context.Context, db *mongo.Database) error {
 	log.Println("Created indexes for achievements collection")
 	return nil
 }
+
+func createAchievementRevisionIndexes(ctx context.Context, db *mongo.Database) error {
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "achievementId", Value: 1}, {Key: "revision", Value: 1}},
+		Options: options.Index().SetName("idx_achievement_revision").SetUnique(true),
+	}
+
+	if _, err := db.Collection("achievement_revisions").Indexes().CreateOne(ctx, indexModel); err != nil {
+		return fmt.Errorf("create achievement revision indexes: %w", err)
+	}
+
+	log.Println("Created indexes for achievement_revisions collection")
+	return nil
+}
@@ -1,17 +1,4 @@
-DROP EXTENSION IF EXISTS "uuid-ossp" CASCADE;
-
-DROP TABLE IF EXISTS refresh_tokens CASCADE;
-DROP TABLE IF EXISTS achievement_references CASCADE;
-DROP TABLE IF EXISTS students CASCADE;
-DROP TABLE IF EXISTS lecturers CASCADE;
-DROP TABLE IF EXISTS role_permissions CASCADE;
-DROP TABLE IF EXISTS users CASCADE;
-DROP TABLE IF EXISTS permissions CASCADE;
-DROP TABLE IF EXISTS roles CASCADE;
-
-DROP TYPE IF EXISTS achievement_status CASCADE;
-
-DROP FUNCTION IF EXISTS update_updated_at_column() CASCADE;
+-- Schema awal: tabel pengguna, RBAC, profil, prestasi, dan refresh token
 
 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 
@@ -0,0 +1,9 @@
+DELETE FROM role_permissions
+WHERE role_id IN (SELECT id FROM roles WHERE name IN ('Admin', 'Mahasiswa', 'Dosen Wali'));
+
+DELETE FROM permissions WHERE name IN (
+    'achievement:create', 'achievement:read', 'achievement:update', 'achievement:delete',
+    'achievement:verify', 'user:manage'
+);
+
+DELETE FROM roles WHERE name IN ('Admin', 'Mahasiswa', 'Dosen Wali');
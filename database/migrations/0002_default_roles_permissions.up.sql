@@ -0,0 +1,32 @@
+-- Data referensi yang dibutuhkan aplikasi: role bawaan, permission, dan pemetaannya
+
+-- Insert Roles
+INSERT INTO roles (name, description) VALUES
+('Admin', 'Pengelola sistem dengan akses penuh'),
+('Mahasiswa', 'Pelapor prestasi'),
+('Dosen Wali', 'Verifikator prestasi mahasiswa bimbingannya');
+
+-- Insert Permissions
+INSERT INTO permissions (name, resource, action, description) VALUES
+('achievement:create', 'achievement', 'create', 'Membuat prestasi baru'),
+('achievement:read', 'achievement', 'read', 'Membaca data prestasi'),
+('achievement:update', 'achievement', 'update', 'Mengupdate data prestasi'),
+('achievement:delete', 'achievement', 'delete', 'Menghapus data prestasi'),
+('achievement:verify', 'achievement', 'verify', 'Memverifikasi prestasi'),
+('user:manage', 'user', 'manage', 'Mengelola pengguna');
+
+-- Insert Role Permissions
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE (r.name = 'Admin' AND p.name IN (
+    'achievement:create', 'achievement:read', 'achievement:update', 
+    'achievement:delete', 'achievement:verify', 'user:manage'
+))
+OR (r.name = 'Mahasiswa' AND p.name IN (
+    'achievement:create', 'achievement:read', 'achievement:update', 'achievement:delete'
+))
+OR (r.name = 'Dosen Wali' AND p.name IN (
+    'achievement:read', 'achievement:verify'
+));
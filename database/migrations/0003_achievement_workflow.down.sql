@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS achievement_outbox CASCADE;
+DROP TABLE IF EXISTS point_rubrics CASCADE;
+DROP TABLE IF EXISTS achievement_status_history CASCADE;
+
+DROP TYPE IF EXISTS point_rubric_status CASCADE;
+DROP TYPE IF EXISTS outbox_status CASCADE;
+
+ALTER TABLE achievement_references DROP CONSTRAINT IF EXISTS achievement_references_mongo_achievement_id_key;
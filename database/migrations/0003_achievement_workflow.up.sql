@@ -0,0 +1,75 @@
+-- Alur prestasi: riwayat status, rubrik poin berversi, dan outbox konsistensi MongoDB/PostgreSQL.
+-- Satu dokumen MongoDB hanya boleh memiliki satu reference.
+
+CREATE TYPE point_rubric_status AS ENUM ('draft', 'published', 'archived');
+CREATE TYPE outbox_status AS ENUM ('pending', 'completed', 'compensated', 'failed');
+
+ALTER TABLE achievement_references
+    ADD CONSTRAINT achievement_references_mongo_achievement_id_key UNIQUE (mongo_achievement_id);
+
+CREATE TABLE achievement_status_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    achievement_reference_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    from_status achievement_status,
+    to_status achievement_status NOT NULL,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    note TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE point_rubrics (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    version INTEGER UNIQUE NOT NULL,
+    status point_rubric_status NOT NULL DEFAULT 'draft',
+    rules JSONB NOT NULL DEFAULT '[]',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    published_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE achievement_outbox (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    operation VARCHAR(20) NOT NULL,
+    mongo_achievement_id VARCHAR(24) NOT NULL,
+    achievement_reference_id UUID REFERENCES achievement_references(id) ON DELETE CASCADE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    idempotency_key VARCHAR(100),
+    status outbox_status NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_achievement_status_history_reference_id ON achievement_status_history(achievement_reference_id);
+CREATE UNIQUE INDEX idx_point_rubrics_single_draft ON point_rubrics(status) WHERE status = 'draft';
+CREATE UNIQUE INDEX idx_point_rubrics_single_published ON point_rubrics(status) WHERE status = 'published';
+CREATE INDEX idx_achievement_outbox_pending ON achievement_outbox(next_attempt_at) WHERE status = 'pending';
+CREATE UNIQUE INDEX idx_achievement_outbox_idempotency_key ON achievement_outbox(created_by, idempotency_key) WHERE idempotency_key IS NOT NULL;
+
+CREATE TRIGGER update_point_rubrics_updated_at BEFORE UPDATE ON point_rubrics
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_achievement_outbox_updated_at BEFORE UPDATE ON achievement_outbox
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Rubrik poin default (versi 1) agar perhitungan poin langsung dapat digunakan
+INSERT INTO point_rubrics (version, status, rules, published_at) VALUES
+(1, 'published', '[
+    {"achievement_type": "competition", "competition_level": "international", "rank": 1, "points": 200},
+    {"achievement_type": "competition", "competition_level": "international", "points": 150},
+    {"achievement_type": "competition", "competition_level": "national", "rank": 1, "points": 120},
+    {"achievement_type": "competition", "competition_level": "national", "points": 100},
+    {"achievement_type": "competition", "competition_level": "regional", "points": 60},
+    {"achievement_type": "competition", "competition_level": "local", "points": 30},
+    {"achievement_type": "publication", "publication_type": "journal", "points": 150},
+    {"achievement_type": "publication", "publication_type": "book", "points": 120},
+    {"achievement_type": "publication", "publication_type": "conference", "points": 100},
+    {"achievement_type": "organization", "position": "Ketua", "points": 80},
+    {"achievement_type": "organization", "points": 40},
+    {"achievement_type": "certification", "points": 60},
+    {"achievement_type": "academic", "points": 50},
+    {"achievement_type": "other", "points": 20}
+]', NOW());
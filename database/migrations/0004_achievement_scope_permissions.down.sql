@@ -0,0 +1,5 @@
+DELETE FROM permissions WHERE name IN (
+    'achievement:read:own', 'achievement:read:advisees', 'achievement:read:all',
+    'achievement:write:own', 'achievement:write:all', 'achievement:verify:advisees',
+    'achievement:verify:all', 'rubric:manage'
+);
@@ -0,0 +1,26 @@
+-- Permission berjangkauan (own, advisees, all) untuk akses prestasi dan permission pengelolaan
+-- rubrik poin, diberikan ke role bawaan sesuai perannya.
+
+INSERT INTO permissions (name, resource, action, description) VALUES
+('achievement:read:own', 'achievement', 'read:own', 'Membaca prestasi milik sendiri'),
+('achievement:read:advisees', 'achievement', 'read:advisees', 'Membaca prestasi mahasiswa bimbingan'),
+('achievement:read:all', 'achievement', 'read:all', 'Membaca seluruh prestasi'),
+('achievement:write:own', 'achievement', 'write:own', 'Membuat dan mengubah prestasi milik sendiri'),
+('achievement:write:all', 'achievement', 'write:all', 'Membuat dan mengubah prestasi seluruh mahasiswa'),
+('achievement:verify:advisees', 'achievement', 'verify:advisees', 'Memverifikasi prestasi mahasiswa bimbingan'),
+('achievement:verify:all', 'achievement', 'verify:all', 'Memverifikasi seluruh prestasi'),
+('rubric:manage', 'rubric', 'manage', 'Mengelola rubrik poin prestasi');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE (r.name = 'Admin' AND p.name IN (
+    'achievement:read:all', 'achievement:write:all', 'achievement:verify:all', 'rubric:manage'
+))
+OR (r.name = 'Mahasiswa' AND p.name IN (
+    'achievement:read:own', 'achievement:write:own'
+))
+OR (r.name = 'Dosen Wali' AND p.name IN (
+    'achievement:read:advisees', 'achievement:verify:advisees'
+));
@@ -0,0 +1,11 @@
+-- Pengaturan tingkat database. Key 'environment' sengaja tidak diisi di sini: penanda harus diatur
+-- secara eksplisit dengan `migrate environment`, dan perintah seeding menolak berjalan tanpanya.
+
+CREATE TABLE app_settings (
+    key VARCHAR(50) PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TRIGGER update_app_settings_updated_at BEFORE UPDATE ON app_settings
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
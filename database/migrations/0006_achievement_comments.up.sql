@@ -0,0 +1,19 @@
+-- Thread komentar per prestasi antara mahasiswa, dosen wali, dan admin. Komentar yang dihapus
+-- hanya ditandai deleted_at agar urutan percakapan tetap utuh.
+
+CREATE TABLE achievement_comments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    achievement_reference_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    body TEXT NOT NULL,
+    attachment_name VARCHAR(255),
+    attachment_url VARCHAR(500),
+    deleted_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_achievement_comments_reference_id ON achievement_comments(achievement_reference_id, created_at);
+
+CREATE TRIGGER update_achievement_comments_updated_at BEFORE UPDATE ON achievement_comments
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
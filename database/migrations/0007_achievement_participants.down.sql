@@ -0,0 +1,3 @@
+ALTER TABLE point_rubrics DROP COLUMN IF EXISTS team_split_rules;
+DROP TABLE IF EXISTS achievement_participants CASCADE;
+DROP TYPE IF EXISTS participant_role;
@@ -0,0 +1,22 @@
+-- Prestasi tim: seluruh peserta beserta perannya, termasuk pemilik reference. Prestasi tanpa baris peserta
+-- adalah prestasi individu milik achievement_references.student_id. Kolom points diisi saat
+-- prestasi diverifikasi dengan pembagian poin dari rubrik yang aktif.
+
+CREATE TYPE participant_role AS ENUM ('leader', 'member');
+
+CREATE TABLE achievement_participants (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    achievement_reference_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    role participant_role NOT NULL,
+    points INTEGER,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (achievement_reference_id, student_id)
+);
+
+CREATE INDEX idx_achievement_participants_student_id ON achievement_participants(student_id);
+CREATE UNIQUE INDEX idx_achievement_participants_single_leader ON achievement_participants(achievement_reference_id) WHERE role = 'leader';
+
+-- Aturan pembagian poin prestasi tim per versi rubrik. Daftar kosong berarti setiap peserta
+-- mendapat poin penuh.
+ALTER TABLE point_rubrics ADD COLUMN team_split_rules JSONB NOT NULL DEFAULT '[]';
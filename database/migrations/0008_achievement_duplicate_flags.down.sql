@@ -0,0 +1 @@
+DROP TABLE IF EXISTS achievement_duplicate_flags CASCADE;
@@ -0,0 +1,20 @@
+-- Tanda kemungkinan duplikat yang ditemukan saat prestasi dibuat atau disubmit, untuk ditinjau
+-- dosen wali. Tanda yang sudah ditinjau ditutup dengan dismissed_at tanpa dihapus.
+
+CREATE TABLE achievement_duplicate_flags (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    achievement_reference_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    matched_mongo_achievement_id VARCHAR(24) NOT NULL,
+    score NUMERIC(4, 3) NOT NULL,
+    reasons TEXT[] NOT NULL DEFAULT '{}',
+    dismissed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    dismissed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (achievement_reference_id, matched_mongo_achievement_id)
+);
+
+CREATE INDEX idx_achievement_duplicate_flags_open ON achievement_duplicate_flags(achievement_reference_id) WHERE dismissed_at IS NULL;
+
+CREATE TRIGGER update_achievement_duplicate_flags_updated_at BEFORE UPDATE ON achievement_duplicate_flags
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
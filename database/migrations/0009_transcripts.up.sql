@@ -0,0 +1,14 @@
+-- Transkrip prestasi (SKPI) yang pernah diterbitkan. Setiap PDF yang diunduh dicatat di sini
+-- beserta daftar prestasi terverifikasi yang tercantum, sehingga kode verifikasi pada dokumen
+-- dapat dicocokkan kembali dengan isi saat diterbitkan.
+
+CREATE TABLE transcripts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    issued_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    achievement_reference_ids UUID[] NOT NULL DEFAULT '{}',
+    total_points INTEGER NOT NULL DEFAULT 0,
+    issued_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_transcripts_student_id ON transcripts(student_id);
@@ -0,0 +1,4 @@
+ALTER TABLE transcripts
+    DROP COLUMN IF EXISTS revocation_reason,
+    DROP COLUMN IF EXISTS revoked_by,
+    DROP COLUMN IF EXISTS revoked_at;
@@ -0,0 +1,7 @@
+-- Pencabutan transkrip yang sudah diterbitkan. Transkrip yang dicabut tetap disimpan, tetapi
+-- pemeriksaan kode verifikasinya menghasilkan status revoked.
+
+ALTER TABLE transcripts
+    ADD COLUMN revoked_at TIMESTAMP,
+    ADD COLUMN revoked_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN revocation_reason TEXT;
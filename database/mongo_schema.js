@@ -11,34 +11,48 @@
 use('sppm_2025');
 
 // Struktur Collection: achievements
+// Nama field mengikuti tag bson pada app/model/mongo (camelCase). Validator $jsonSchema dengan
+// struktur yang sama dipasang oleh `go run cmd/migrate/main.go up` (lihat database/mongo_validator.go),
+// dan `go run cmd/migrate/main.go check-mongo` menampilkan dokumen lama yang tidak sesuai.
 // {
 //   _id: ObjectId,
-//   studentId: String (UUID dari PostgreSQL students.id),
-//   achievementType: String, // 'academic', 'competition', 'organization', 'publication', 'certification', 'other'
-//   title: String,
-//   description: String,
-//   details: Object (field dinamis berdasarkan achievementType),
-//   attachments: Array (optional),
-//   tags: Array (optional),
-//   points: Number,
-//   createdAt: Date,
-//   updatedAt: Date
+//   studentId: String (wajib, UUID dari PostgreSQL students.id),
+//   achievementType: String (wajib), // 'academic', 'competition', 'organization', 'publication', 'certification', 'other'
+//   title: String (wajib, tidak kosong),
+//   description: String (wajib, tidak kosong),
+//   details: Object (wajib, field dinamis berdasarkan achievementType) {
+//     competitionName: String, competitionLevel: 'international' | 'national' | 'regional' | 'local',
+//     rank: Int (>= 1), medalType: String,
+//     publicationType: 'journal' | 'conference' | 'book', publicationTitle: String,
+//     authors: Array<String>, publisher: String, issn: String,
+//     organizationName: String, position: String, period: { start: Date, end: Date },
+//     certificationName: String, issuedBy: String, certificationNumber: String, validUntil: Date,
+//     eventDate: Date, location: String, organizer: String, score: Number,
+//     customFields: Object
+//   },
+//   attachments: Array (optional) [{ fileName: String, fileUrl: String, fileType: String, checksum: String (SHA-256, diisi server), uploadedAt: Date }],
+//   tags: Array<String> (optional),
+//   points: Int (wajib, >= 0, dihitung server dari rubrik poin),
+//   pointRubricVersion: Int (optional),
+//   deletedAt: Date (optional, terisi jika soft delete),
+//   createdAt: Date (wajib),
+//   updatedAt: Date (wajib)
 // }
 
 // Query Examples untuk Navicat
 
-// 1. Find all achievements by student_id
-// db.achievements.find({ "student_id": "550e8400-e29b-41d4-a716-446655440000" })
+// 1. Find all achievements by studentId (UUID dari PostgreSQL)
+// db.achievements.find({ "studentId": "550e8400-e29b-41d4-a716-446655440000" })
 
 // 2. Find achievements by type
-// db.achievements.find({ "achievement_type": "competition" })
+// db.achievements.find({ "achievementType": "competition" })
 
-// 3. Find achievements with text search
+// 3. Find achievements with text search (requires text index)
 // db.achievements.find({ $text: { $search: "programming" } })
 
 // 4. Find achievements by date range
 // db.achievements.find({
-//   "created_at": {
+//   "createdAt": {
 //     $gte: new Date("2025-01-01T00:00:00Z"),
 //     $lte: new Date("2025-12-31T23:59:59Z")
 //   }
@@ -49,19 +63,20 @@ use('sppm_2025');
 
 // 6. Find achievements by competition level
 // db.achievements.find({
-//   "achievement_type": "competition",
-//   "details.competition_level": "national"
+//   "achievementType": "competition",
+//   "details.competitionLevel": "national"
 // })
 
 // 7. Aggregate: Count achievements by type
 // db.achievements.aggregate([
-//   { $group: { _id: "$achievement_type", count: { $sum: 1 } } }
+//   { $group: { _id: "$achievementType", count: { $sum: 1 } } }
 // ])
 
 // 8. Aggregate: Sum points by student
 // db.achievements.aggregate([
-//   { $group: { _id: "$student_id", total_points: { $sum: "$points" } } },
-//   { $sort: { total_points: -1 } }
+//   { $match: { "deletedAt": { $exists: false } } },
+//   { $group: { _id: "$studentId", totalPoints: { $sum: "$points" } } },
+//   { $sort: { totalPoints: -1 } }
 // ])
 
 // 9. Find achievements with attachments
@@ -70,18 +85,8 @@ use('sppm_2025');
 // 10. Find achievements by points range
 // db.achievements.find({ "points": { $gte: 100, $lte: 200 } })
 
-// 11. Find achievements by studentId (UUID dari PostgreSQL)
-// db.achievements.find({ "studentId": "550e8400-e29b-41d4-a716-446655440000" })
-
-// 12. Find achievements by achievementType
-// db.achievements.find({ "achievementType": "competition" })
-
-// 13. Find achievements with text search (requires text index)
-// db.achievements.find({ $text: { $search: "programming" } })
-
-// 14. Count total achievements
-// db.achievements.countDocuments({})
-
-// 15. Count achievements by type
+// 11. Count achievements by type
 // db.achievements.countDocuments({ "achievementType": "competition" })
 
+// 12. Show the installed validator
+// db.getCollectionInfos({ name: "achievements" })[0].options.validator
@@ -0,0 +1,154 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// InvalidAchievement adalah ringkasan dokumen yang tidak lolos validator koleksi achievements.
+type InvalidAchievement struct {
+	ID              primitive.ObjectID `bson:"_id"`
+	StudentID       interface{}        `bson:"studentId"`
+	AchievementType interface{}        `bson:"achievementType"`
+	Title           interface{}        `bson:"title"`
+}
+
+var (
+	bsonString  = bson.M{"bsonType": "string"}
+	bsonDate    = bson.M{"bsonType": "date"}
+	bsonInteger = bson.A{"int", "long"}
+)
+
+// AchievementJSONSchema mengembalikan $jsonSchema yang sesuai dengan modelmongo.Achievement.
+// Field tambahan tetap diizinkan agar field baru dapat ditambahkan tanpa mengubah validator.
+func AchievementJSONSchema() bson.M {
+	return bson.M{
+		"bsonType": "object",
+		"required": bson.A{"studentId", "achievementType", "title", "description", "details", "points", "createdAt", "updatedAt"},
+		"properties": bson.M{
+			"_id":       bson.M{"bsonType": "objectId"},
+			"studentId": bson.M{"bsonType": "string", "pattern": "^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$"},
+			"achievementType": bson.M{
+				"bsonType": "string",
+				"enum":     bson.A{"academic", "competition", "organization", "publication", "certification", "other"},
+			},
+			"title":       bson.M{"bsonType": "string", "minLength": 1},
+			"description": bson.M{"bsonType": "string", "minLength": 1},
+			"details": bson.M{
+				"bsonType": "object",
+				"properties": bson.M{
+					"competitionName":  bsonString,
+					"competitionLevel": bson.M{"bsonType": "string", "enum": bson.A{"international", "national", "regional", "local"}},
+					"rank":             bson.M{"bsonType": bsonInteger, "minimum": 1},
+					"medalType":        bsonString,
+					"publicationType":  bson.M{"bsonType": "string", "enum": bson.A{"journal", "conference", "book"}},
+					"publicationTitle": bsonString,
+					"authors":          bson.M{"bsonType": "array", "items": bsonString},
+					"publisher":        bsonString,
+					"issn":             bsonString,
+					"organizationName": bsonString,
+					"position":         bsonString,
+					"period": bson.M{
+						"bsonType":   "object",
+						"required":   bson.A{"start", "end"},
+						"properties": bson.M{"start": bsonDate, "end": bsonDate},
+					},
+					"certificationName":   bsonString,
+					"issuedBy":            bsonString,
+					"certificationNumber": bsonString,
+					"validUntil":          bsonDate,
+					"eventDate":           bsonDate,
+					"location":            bsonString,
+					"organizer":           bsonString,
+					"score":               bson.M{"bsonType": bson.A{"double", "int", "long", "decimal"}},
+					"customFields":        bson.M{"bsonType": "object"},
+				},
+			},
+			"attachments": bson.M{
+				"bsonType": "array",
+				"items": bson.M{
+					"bsonType": "object",
+					"required": bson.A{"fileName", "fileUrl"},
+					"properties": bson.M{
+						"fileName":   bsonString,
+						"fileUrl":    bsonString,
+						"fileType":   bsonString,
+						"checksum":   bsonString,
+						"uploadedAt": bsonDate,
+					},
+				},
+			},
+			"tags":               bson.M{"bsonType": "array", "items": bsonString},
+			"points":             bson.M{"bsonType": bsonInteger, "minimum": 0},
+			"pointRubricVersion": bson.M{"bsonType": bsonInteger, "minimum": 0},
+			"deletedAt":          bsonDate,
+			"createdAt":          bsonDate,
+			"updatedAt":          bsonDate,
+		},
+	}
+}
+
+// ensureAchievementValidator memasang validator pada koleksi achievements, membuat koleksi jika
+// belum ada. Validation level moderate dipakai agar dokumen lama yang tidak valid tetap dapat
+// diupdate sambil diperbaiki; temukan dokumen tersebut dengan FindInvalidAchievements.
+func ensureAchievementValidator(ctx context.Context, db *mongo.Database) error {
+	validator := bson.M{"$jsonSchema": AchievementJSONSchema()}
+
+	names, err := db.ListCollectionNames(ctx, bson.M{"name": "achievements"})
+	if err != nil {
+		return fmt.Errorf("list collections for achievements: %w", err)
+	}
+
+	if len(names) == 0 {
+		opts := options.CreateCollection().
+			SetValidator(validator).
+			SetValidationLevel("moderate").
+			SetValidationAction("error")
+		if err := db.CreateCollection(ctx, "achievements", opts); err != nil {
+			return fmt.Errorf("create achievements collection: %w", err)
+		}
+	} else {
+		command := bson.D{
+			{Key: "collMod", Value: "achievements"},
+			{Key: "validator", Value: validator},
+			{Key: "validationLevel", Value: "moderate"},
+			{Key: "validationAction", Value: "error"},
+		}
+		if err := db.RunCommand(ctx, command).Err(); err != nil {
+			return fmt.Errorf("update achievements validator: %w", err)
+		}
+	}
+
+	log.Println("Installed JSON schema validator for achievements collection")
+	return nil
+}
+
+// FindInvalidAchievements mengembalikan dokumen achievements yang tidak sesuai dengan
+// AchievementJSONSchema, misalnya dokumen yang ditulis langsung oleh script sebelum validator dipasang.
+func FindInvalidAchievements(db *mongo.Database) ([]InvalidAchievement, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"$nor": bson.A{bson.M{"$jsonSchema": AchievementJSONSchema()}}}
+	projection := bson.M{"_id": 1, "studentId": 1, "achievementType": 1, "title": 1}
+
+	cursor, err := db.Collection("achievements").Find(ctx, filter, options.Find().SetProjection(projection).SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	invalid := []InvalidAchievement{}
+	if err := cursor.All(ctx, &invalid); err != nil {
+		return nil, err
+	}
+
+	return invalid, nil
+}
@@ -0,0 +1,61 @@
+package helper
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ErrUnsupportedSpreadsheet dikembalikan jika ekstensi file bukan .csv atau .xlsx.
+var ErrUnsupportedSpreadsheet = errors.New("format file tidak didukung, gunakan .csv atau .xlsx")
+
+// ReadSpreadsheet membaca seluruh baris file CSV atau sheet pertama file XLSX berdasarkan ekstensi
+// filename. Nilai sel XLSX dibaca mentah sehingga tanggal berupa nomor seri Excel.
+func ReadSpreadsheet(filename string, r io.Reader) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+
+		// Baris kosong dilewati oleh csv.Reader; baris pengganti ditambahkan agar indeks record
+		// tetap sama dengan nomor baris file seperti pada XLSX.
+		records := [][]string{}
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			line, _ := reader.FieldPos(0)
+			for len(records) < line-1 {
+				records = append(records, []string{})
+			}
+			records = append(records, record)
+		}
+		if len(records) > 0 && len(records[0]) > 0 {
+			records[0][0] = strings.TrimPrefix(records[0][0], "\ufeff")
+		}
+		return records, nil
+	case ".xlsx":
+		file, err := excelize.OpenReader(r, excelize.Options{RawCellValue: true})
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		sheets := file.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, fmt.Errorf("file xlsx tidak memiliki sheet")
+		}
+		return file.GetRows(sheets[0], excelize.Options{RawCellValue: true})
+	}
+	return nil, ErrUnsupportedSpreadsheet
+}
@@ -2,11 +2,16 @@ package helper
 
 import (
 	"database/sql"
+	"html"
+	"net/mail"
+	"regexp"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 func SuccessResponse(c *fiber.Ctx, statusCode int, message string, data interface{}) error {
@@ -29,6 +34,16 @@ func ValidationErrorResponse(c *fiber.Ctx, message string) error {
 	return ErrorResponse(c, fiber.StatusBadRequest, message)
 }
 
+func FieldErrorsResponse(c *fiber.Ctx, message string, errors interface{}) error {
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"status": "error",
+		"data": fiber.Map{
+			"message": message,
+			"errors":  errors,
+		},
+	})
+}
+
 func UnauthorizedResponse(c *fiber.Ctx, message string) error {
 	return ErrorResponse(c, fiber.StatusUnauthorized, message)
 }
@@ -60,6 +75,22 @@ func HandleDatabaseError(c *fiber.Ctx, err error) error {
 	return InternalServerErrorResponse(c, "Error mengakses database. Detail: "+err.Error())
 }
 
+func IsUniqueViolation(err error) (string, bool) {
+	pqErr, ok := err.(*pq.Error)
+	if !ok || pqErr.Code != "23505" {
+		return "", false
+	}
+	return pqErr.Constraint, true
+}
+
+func IsForeignKeyViolation(err error) (string, bool) {
+	pqErr, ok := err.(*pq.Error)
+	if !ok || pqErr.Code != "23503" {
+		return "", false
+	}
+	return pqErr.Constraint, true
+}
+
 func ParseUUID(id string) (uuid.UUID, error) {
 	return uuid.Parse(id)
 }
@@ -128,6 +159,83 @@ func SanitizeSearch(search string) string {
 	return search
 }
 
+func SearchTerms(query string) []string {
+	var terms []string
+	for _, field := range strings.Fields(query) {
+		if strings.HasPrefix(field, "-") {
+			continue
+		}
+		field = strings.Trim(field, "\"'.,;:!?()")
+		if field != "" {
+			terms = append(terms, field)
+		}
+	}
+	return terms
+}
+
+// HighlightText membungkus kemunculan terms di text dengan <em></em>. Jika text lebih panjang
+// dari maxLength byte, hanya cuplikan di sekitar kemunculan pertama yang dikembalikan.
+// Hasilnya berupa HTML: teks di luar tag <em> di-escape karena berasal dari input mahasiswa.
+// String kosong dikembalikan jika tidak ada term yang cocok.
+func HighlightText(text string, terms []string, maxLength int) string {
+	if len(terms) == 0 {
+		return ""
+	}
+
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = regexp.QuoteMeta(term)
+	}
+	pattern := regexp.MustCompile("(?i)" + strings.Join(quoted, "|"))
+
+	loc := pattern.FindStringIndex(text)
+	if loc == nil {
+		return ""
+	}
+
+	snippet := text
+	if maxLength > 0 && len(text) > maxLength {
+		start := loc[0] - maxLength/2
+		if start < 0 {
+			start = 0
+		}
+		end := start + maxLength
+		if end > len(text) {
+			end = len(text)
+			start = end - maxLength
+		}
+		for start > 0 && !utf8.RuneStart(text[start]) {
+			start--
+		}
+		for end < len(text) && !utf8.RuneStart(text[end]) {
+			end++
+		}
+
+		snippet = text[start:end]
+		if start > 0 {
+			snippet = "..." + snippet
+		}
+		if end < len(text) {
+			snippet = snippet + "..."
+		}
+	}
+
+	var highlighted strings.Builder
+	last := 0
+	for _, match := range pattern.FindAllStringIndex(snippet, -1) {
+		highlighted.WriteString(html.EscapeString(snippet[last:match[0]]))
+		highlighted.WriteString("<em>" + html.EscapeString(snippet[match[0]:match[1]]) + "</em>")
+		last = match[1]
+	}
+	highlighted.WriteString(html.EscapeString(snippet[last:]))
+	return highlighted.String()
+}
+
+func IsValidEmail(email string) bool {
+	address, err := mail.ParseAddress(email)
+	return err == nil && address.Address == email
+}
+
 func IsEmptyString(s string) bool {
 	return strings.TrimSpace(s) == ""
 }
@@ -3,11 +3,13 @@ package main
 import (
 	"log"
 	"os"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
 	"sistem-pelaporan-prestasi-mahasiswa/config"
 	configmongo "sistem-pelaporan-prestasi-mahasiswa/config/mongo"
 	"sistem-pelaporan-prestasi-mahasiswa/database"
 	"sistem-pelaporan-prestasi-mahasiswa/middleware"
 	routepostgre "sistem-pelaporan-prestasi-mahasiswa/route/postgre"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -29,11 +31,18 @@ func main() {
 
 	mongoDB := database.ConnectMongoDB()
 
+	go servicepostgre.StartAchievementOutboxWorker(postgresDB, mongoDB, 30*time.Second)
+
 	app := configmongo.NewApp()
 	app.Use(middleware.LoggerMiddleware)
 
 	routepostgre.UserRoutes(app, postgresDB, serverInstanceID)
 	routepostgre.AchievementRoutes(app, postgresDB, mongoDB)
+	routepostgre.StudentRoutes(app, postgresDB, mongoDB)
+	routepostgre.LecturerRoutes(app, postgresDB)
+	routepostgre.RoleRoutes(app, postgresDB)
+	routepostgre.PointRubricRoutes(app, postgresDB, mongoDB)
+	routepostgre.VerificationRoutes(app, postgresDB, mongoDB)
 
 	port := os.Getenv("APP_PORT")
 	if port == "" {
@@ -20,10 +20,66 @@ func AchievementRoutes(app *fiber.App, postgresDB *sql.DB, mongoDB *mongo.Databa
 		return servicepostgre.GetAchievementsService(c, postgresDB, mongoDB)
 	})
 
+	achievements.Get("/search", middlewarepostgre.PermissionRequired(postgresDB, "achievement:read"), func(c *fiber.Ctx) error {
+		return servicepostgre.SearchAchievementsService(c, postgresDB, mongoDB)
+	})
+
+	achievements.Get("/export", middlewarepostgre.PermissionRequired(postgresDB, "achievement:read"), func(c *fiber.Ctx) error {
+		return servicepostgre.ExportAchievementsService(c, postgresDB, mongoDB)
+	})
+
+	achievements.Get("/duplicates", middlewarepostgre.PermissionRequired(postgresDB, "achievement:verify"), func(c *fiber.Ctx) error {
+		return servicepostgre.GetDuplicateReportService(c, postgresDB, mongoDB)
+	})
+
 	achievements.Get("/:id", middlewarepostgre.PermissionRequired(postgresDB, "achievement:read"), func(c *fiber.Ctx) error {
 		return servicepostgre.GetAchievementByIDService(c, postgresDB, mongoDB)
 	})
 
+	achievements.Get("/:id/history", middlewarepostgre.PermissionRequired(postgresDB, "achievement:read"), func(c *fiber.Ctx) error {
+		return servicepostgre.GetAchievementHistoryService(c, postgresDB)
+	})
+
+	achievements.Get("/:id/revisions", middlewarepostgre.PermissionRequired(postgresDB, "achievement:read"), func(c *fiber.Ctx) error {
+		return servicepostgre.GetAchievementRevisionsService(c, postgresDB, mongoDB)
+	})
+
+	achievements.Get("/:id/revisions/diff", middlewarepostgre.PermissionRequired(postgresDB, "achievement:read"), func(c *fiber.Ctx) error {
+		return servicepostgre.GetAchievementRevisionDiffService(c, postgresDB, mongoDB)
+	})
+
+	achievements.Get("/:id/participants", middlewarepostgre.PermissionRequired(postgresDB, "achievement:read"), func(c *fiber.Ctx) error {
+		return servicepostgre.GetAchievementParticipantsService(c, postgresDB, mongoDB)
+	})
+
+	achievements.Put("/:id/participants", middlewarepostgre.PermissionRequired(postgresDB, "achievement:update"), func(c *fiber.Ctx) error {
+		return servicepostgre.SetAchievementParticipantsService(c, postgresDB, mongoDB)
+	})
+
+	achievements.Get("/:id/duplicates", middlewarepostgre.PermissionRequired(postgresDB, "achievement:verify"), func(c *fiber.Ctx) error {
+		return servicepostgre.GetAchievementDuplicateFlagsService(c, postgresDB)
+	})
+
+	achievements.Post("/:id/duplicates/:flagId/dismiss", middlewarepostgre.PermissionRequired(postgresDB, "achievement:verify"), func(c *fiber.Ctx) error {
+		return servicepostgre.DismissAchievementDuplicateFlagService(c, postgresDB)
+	})
+
+	achievements.Get("/:id/comments", middlewarepostgre.PermissionRequired(postgresDB, "achievement:read"), func(c *fiber.Ctx) error {
+		return servicepostgre.GetAchievementCommentsService(c, postgresDB)
+	})
+
+	achievements.Post("/:id/comments", middlewarepostgre.PermissionRequired(postgresDB, "achievement:read"), func(c *fiber.Ctx) error {
+		return servicepostgre.CreateAchievementCommentService(c, postgresDB)
+	})
+
+	achievements.Put("/:id/comments/:commentId", middlewarepostgre.PermissionRequired(postgresDB, "achievement:read"), func(c *fiber.Ctx) error {
+		return servicepostgre.UpdateAchievementCommentService(c, postgresDB)
+	})
+
+	achievements.Delete("/:id/comments/:commentId", middlewarepostgre.PermissionRequired(postgresDB, "achievement:read"), func(c *fiber.Ctx) error {
+		return servicepostgre.DeleteAchievementCommentService(c, postgresDB)
+	})
+
 	achievements.Post("", middlewarepostgre.PermissionRequired(postgresDB, "achievement:create"), func(c *fiber.Ctx) error {
 		return servicepostgre.CreateAchievementService(c, postgresDB, mongoDB)
 	})
@@ -36,8 +92,20 @@ func AchievementRoutes(app *fiber.App, postgresDB *sql.DB, mongoDB *mongo.Databa
 		return servicepostgre.UploadFileService(c)
 	})
 
+	achievements.Post("/import", middlewarepostgre.PermissionRequired(postgresDB, "achievement:create"), func(c *fiber.Ctx) error {
+		return servicepostgre.ImportAchievementsService(c, postgresDB, mongoDB)
+	})
+
 	achievements.Post("/:id/submit", middlewarepostgre.PermissionRequired(postgresDB, "achievement:update"), func(c *fiber.Ctx) error {
-		return servicepostgre.SubmitAchievementService(c, postgresDB)
+		return servicepostgre.SubmitAchievementService(c, postgresDB, mongoDB)
+	})
+
+	achievements.Post("/:id/verify", middlewarepostgre.PermissionRequired(postgresDB, "achievement:verify"), func(c *fiber.Ctx) error {
+		return servicepostgre.VerifyAchievementService(c, postgresDB, mongoDB)
+	})
+
+	achievements.Post("/:id/reject", middlewarepostgre.PermissionRequired(postgresDB, "achievement:verify"), func(c *fiber.Ctx) error {
+		return servicepostgre.RejectAchievementService(c, postgresDB)
 	})
 
 	achievements.Delete("/:id", middlewarepostgre.PermissionRequired(postgresDB, "achievement:delete"), func(c *fiber.Ctx) error {
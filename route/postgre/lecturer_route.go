@@ -0,0 +1,37 @@
+package route
+
+import (
+	"database/sql"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+	middlewarepostgre "sistem-pelaporan-prestasi-mahasiswa/middleware/postgre"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func LecturerRoutes(app *fiber.App, db *sql.DB) {
+	lecturers := app.Group("/api/v1/lecturers", middlewarepostgre.AuthRequired())
+
+	lecturers.Get("", middlewarepostgre.PermissionRequired(db, "user:manage"), func(c *fiber.Ctx) error {
+		return servicepostgre.GetLecturersService(c, db)
+	})
+
+	lecturers.Get("/:id", middlewarepostgre.PermissionRequired(db, "user:manage"), func(c *fiber.Ctx) error {
+		return servicepostgre.GetLecturerByIDService(c, db)
+	})
+
+	lecturers.Get("/:id/advisees", func(c *fiber.Ctx) error {
+		return servicepostgre.GetLecturerAdviseesService(c, db)
+	})
+
+	lecturers.Post("", middlewarepostgre.PermissionRequired(db, "user:manage"), func(c *fiber.Ctx) error {
+		return servicepostgre.CreateLecturerService(c, db)
+	})
+
+	lecturers.Put("/:id", middlewarepostgre.PermissionRequired(db, "user:manage"), func(c *fiber.Ctx) error {
+		return servicepostgre.UpdateLecturerService(c, db)
+	})
+
+	lecturers.Delete("/:id", middlewarepostgre.PermissionRequired(db, "user:manage"), func(c *fiber.Ctx) error {
+		return servicepostgre.DeleteLecturerService(c, db)
+	})
+}
@@ -0,0 +1,42 @@
+package route
+
+import (
+	"database/sql"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+	middlewarepostgre "sistem-pelaporan-prestasi-mahasiswa/middleware/postgre"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func PointRubricRoutes(app *fiber.App, postgresDB *sql.DB, mongoDB *mongo.Database) {
+	rubrics := app.Group("/api/v1/point-rubrics", middlewarepostgre.AuthRequired())
+
+	rubrics.Get("/active", func(c *fiber.Ctx) error {
+		return servicepostgre.GetActivePointRubricService(c, postgresDB)
+	})
+
+	rubrics.Get("", middlewarepostgre.PermissionRequired(postgresDB, "rubric:manage"), func(c *fiber.Ctx) error {
+		return servicepostgre.GetPointRubricsService(c, postgresDB)
+	})
+
+	rubrics.Get("/draft", middlewarepostgre.PermissionRequired(postgresDB, "rubric:manage"), func(c *fiber.Ctx) error {
+		return servicepostgre.GetDraftPointRubricService(c, postgresDB)
+	})
+
+	rubrics.Put("/draft", middlewarepostgre.PermissionRequired(postgresDB, "rubric:manage"), func(c *fiber.Ctx) error {
+		return servicepostgre.SaveDraftPointRubricService(c, postgresDB)
+	})
+
+	rubrics.Delete("/draft", middlewarepostgre.PermissionRequired(postgresDB, "rubric:manage"), func(c *fiber.Ctx) error {
+		return servicepostgre.DeleteDraftPointRubricService(c, postgresDB)
+	})
+
+	rubrics.Get("/draft/preview", middlewarepostgre.PermissionRequired(postgresDB, "rubric:manage"), func(c *fiber.Ctx) error {
+		return servicepostgre.PreviewDraftPointRubricService(c, postgresDB, mongoDB)
+	})
+
+	rubrics.Post("/draft/publish", middlewarepostgre.PermissionRequired(postgresDB, "rubric:manage"), func(c *fiber.Ctx) error {
+		return servicepostgre.PublishDraftPointRubricService(c, postgresDB)
+	})
+}
@@ -0,0 +1,63 @@
+package route
+
+import (
+	"database/sql"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+	middlewarepostgre "sistem-pelaporan-prestasi-mahasiswa/middleware/postgre"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func RoleRoutes(app *fiber.App, db *sql.DB) {
+	roles := app.Group("/api/v1/roles", middlewarepostgre.AuthRequired(), middlewarepostgre.PermissionRequired(db, "user:manage"))
+
+	roles.Get("", func(c *fiber.Ctx) error {
+		return servicepostgre.GetRolesService(c, db)
+	})
+
+	roles.Get("/:id", func(c *fiber.Ctx) error {
+		return servicepostgre.GetRoleByIDService(c, db)
+	})
+
+	roles.Post("", func(c *fiber.Ctx) error {
+		return servicepostgre.CreateRoleService(c, db)
+	})
+
+	roles.Put("/:id", func(c *fiber.Ctx) error {
+		return servicepostgre.UpdateRoleService(c, db)
+	})
+
+	roles.Delete("/:id", func(c *fiber.Ctx) error {
+		return servicepostgre.DeleteRoleService(c, db)
+	})
+
+	roles.Get("/:id/permissions", func(c *fiber.Ctx) error {
+		return servicepostgre.GetRolePermissionsService(c, db)
+	})
+
+	roles.Post("/:id/permissions", func(c *fiber.Ctx) error {
+		return servicepostgre.GrantRolePermissionService(c, db)
+	})
+
+	roles.Delete("/:id/permissions/:permissionId", func(c *fiber.Ctx) error {
+		return servicepostgre.RevokeRolePermissionService(c, db)
+	})
+
+	permissions := app.Group("/api/v1/permissions", middlewarepostgre.AuthRequired(), middlewarepostgre.PermissionRequired(db, "user:manage"))
+
+	permissions.Get("", func(c *fiber.Ctx) error {
+		return servicepostgre.GetPermissionsService(c, db)
+	})
+
+	permissions.Post("", func(c *fiber.Ctx) error {
+		return servicepostgre.CreatePermissionService(c, db)
+	})
+
+	permissions.Put("/:id", func(c *fiber.Ctx) error {
+		return servicepostgre.UpdatePermissionService(c, db)
+	})
+
+	permissions.Delete("/:id", func(c *fiber.Ctx) error {
+		return servicepostgre.DeletePermissionService(c, db)
+	})
+}
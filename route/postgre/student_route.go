@@ -0,0 +1,50 @@
+package route
+
+import (
+	"database/sql"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+	middlewarepostgre "sistem-pelaporan-prestasi-mahasiswa/middleware/postgre"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func StudentRoutes(app *fiber.App, db *sql.DB, mongoDB *mongo.Database) {
+	students := app.Group("/api/v1/students", middlewarepostgre.AuthRequired())
+
+	students.Get("", middlewarepostgre.PermissionRequired(db, "user:manage"), func(c *fiber.Ctx) error {
+		return servicepostgre.GetStudentsService(c, db)
+	})
+
+	students.Get("/:id", middlewarepostgre.PermissionRequired(db, "user:manage"), func(c *fiber.Ctx) error {
+		return servicepostgre.GetStudentByIDService(c, db)
+	})
+
+	students.Get("/:id/transcript.pdf", middlewarepostgre.PermissionRequired(db, "achievement:read"), func(c *fiber.Ctx) error {
+		return servicepostgre.GetStudentTranscriptService(c, db, mongoDB)
+	})
+
+	students.Get("/:id/transcripts", middlewarepostgre.PermissionRequired(db, "achievement:read"), func(c *fiber.Ctx) error {
+		return servicepostgre.GetStudentTranscriptsService(c, db)
+	})
+
+	students.Post("/:id/transcripts/:transcriptId/revoke", middlewarepostgre.PermissionRequired(db, "achievement:verify"), func(c *fiber.Ctx) error {
+		return servicepostgre.RevokeTranscriptService(c, db)
+	})
+
+	students.Post("", middlewarepostgre.PermissionRequired(db, "user:manage"), func(c *fiber.Ctx) error {
+		return servicepostgre.CreateStudentService(c, db)
+	})
+
+	students.Post("/import", middlewarepostgre.PermissionRequired(db, "user:manage"), func(c *fiber.Ctx) error {
+		return servicepostgre.ImportStudentsService(c, db)
+	})
+
+	students.Put("/:id", middlewarepostgre.PermissionRequired(db, "user:manage"), func(c *fiber.Ctx) error {
+		return servicepostgre.UpdateStudentService(c, db)
+	})
+
+	students.Delete("/:id", middlewarepostgre.PermissionRequired(db, "user:manage"), func(c *fiber.Ctx) error {
+		return servicepostgre.DeleteStudentService(c, db)
+	})
+}
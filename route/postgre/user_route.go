@@ -33,5 +33,30 @@ func UserRoutes(app *fiber.App, db *sql.DB, instanceID string) {
 	protected.Get("/profile", func(c *fiber.Ctx) error {
 		return servicepostgre.GetProfileService(c, db)
 	})
-}
 
+	users := app.Group("/api/v1/users", middlewarepostgre.AuthRequired(), middlewarepostgre.PermissionRequired(db, "user:manage"))
+
+	users.Get("", func(c *fiber.Ctx) error {
+		return servicepostgre.GetUsersService(c, db)
+	})
+
+	users.Get("/:id", func(c *fiber.Ctx) error {
+		return servicepostgre.GetUserByIDService(c, db)
+	})
+
+	users.Post("", func(c *fiber.Ctx) error {
+		return servicepostgre.CreateUserService(c, db)
+	})
+
+	users.Put("/:id", func(c *fiber.Ctx) error {
+		return servicepostgre.UpdateUserService(c, db)
+	})
+
+	users.Post("/:id/deactivate", func(c *fiber.Ctx) error {
+		return servicepostgre.DeactivateUserService(c, db)
+	})
+
+	users.Delete("/:id", func(c *fiber.Ctx) error {
+		return servicepostgre.DeleteUserService(c, db)
+	})
+}
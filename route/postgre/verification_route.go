@@ -0,0 +1,17 @@
+package route
+
+import (
+	"database/sql"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// VerificationRoutes mendaftarkan endpoint publik tanpa autentikasi untuk memeriksa keaslian
+// transkrip dan prestasi dari kode verifikasinya.
+func VerificationRoutes(app *fiber.App, postgresDB *sql.DB, mongoDB *mongo.Database) {
+	app.Get("/api/v1/verify/:code", func(c *fiber.Ctx) error {
+		return servicepostgre.VerifyCodeService(c, postgresDB, mongoDB)
+	})
+}
@@ -0,0 +1,117 @@
+package postgre
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"net/url"
+	"os"
+	model "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// verificationSignatureLength adalah panjang HMAC yang disimpan di kode verifikasi (80 bit),
+// cukup untuk mencegah pemalsuan tanpa membuat QR code terlalu padat.
+const verificationSignatureLength = 10
+
+// minVerificationSecretLength adalah panjang minimal VERIFICATION_SECRET.
+const minVerificationSecretLength = 32
+
+var (
+	ErrVerificationSecretMissing  = errors.New("VERIFICATION_SECRET belum diatur atau kurang dari 32 karakter")
+	ErrVerificationBaseURLMissing = errors.New("VERIFICATION_BASE_URL belum diatur atau bukan URL http(s) yang valid")
+	ErrInvalidVerificationCode    = errors.New("kode verifikasi tidak valid")
+)
+
+var verificationKinds = map[string]byte{
+	model.VerificationTypeTranscript:  'T',
+	model.VerificationTypeAchievement: 'A',
+}
+
+var verificationEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// getVerificationSecret membaca VERIFICATION_SECRET saat dipakai. Kunci ini sengaja terpisah dari
+// JWT_SECRET dan tidak memiliki nilai default, karena kode verifikasi dapat diperiksa publik.
+func getVerificationSecret() ([]byte, error) {
+	secret := os.Getenv("VERIFICATION_SECRET")
+	if len(secret) < minVerificationSecretLength {
+		return nil, ErrVerificationSecretMissing
+	}
+	return []byte(secret), nil
+}
+
+// GenerateVerificationCode membuat kode verifikasi bertanda tangan HMAC-SHA256 untuk dokumen
+// bertipe kind (transcript atau achievement) dengan ID UUID. Kode berupa base32 huruf besar.
+func GenerateVerificationCode(kind string, id string) (string, error) {
+	secret, err := getVerificationSecret()
+	if err != nil {
+		return "", err
+	}
+
+	kindByte, ok := verificationKinds[kind]
+	if !ok {
+		return "", errors.New("tipe kode verifikasi tidak dikenal: " + kind)
+	}
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return "", err
+	}
+
+	payload := append([]byte{kindByte}, parsedID[:]...)
+	return verificationEncoding.EncodeToString(append(payload, signVerificationPayload(secret, payload)...)), nil
+}
+
+// ParseVerificationCode memeriksa tanda tangan kode verifikasi dan mengembalikan tipe dokumen serta
+// ID-nya. Huruf kecil, spasi, dan tanda hubung diabaikan agar kode dapat diketik ulang.
+func ParseVerificationCode(code string) (string, string, error) {
+	secret, err := getVerificationSecret()
+	if err != nil {
+		return "", "", err
+	}
+
+	normalized := strings.NewReplacer("-", "", " ", "").Replace(strings.ToUpper(strings.TrimSpace(code)))
+	raw, err := verificationEncoding.DecodeString(normalized)
+	// Karakter terakhir base32 memiliki bit sisa yang diabaikan decoder, sehingga hanya bentuk
+	// kanonis yang diterima agar satu dokumen tidak memiliki beberapa kode.
+	if err != nil || len(raw) != 1+16+verificationSignatureLength || verificationEncoding.EncodeToString(raw) != normalized {
+		return "", "", ErrInvalidVerificationCode
+	}
+
+	payload, signature := raw[:17], raw[17:]
+	if !hmac.Equal(signature, signVerificationPayload(secret, payload)) {
+		return "", "", ErrInvalidVerificationCode
+	}
+
+	for kind, kindByte := range verificationKinds {
+		if payload[0] == kindByte {
+			id, err := uuid.FromBytes(payload[1:])
+			if err != nil {
+				return "", "", ErrInvalidVerificationCode
+			}
+			return kind, id.String(), nil
+		}
+	}
+	return "", "", ErrInvalidVerificationCode
+}
+
+// VerificationURL mengembalikan alamat publik pemeriksaan kode dari VERIFICATION_BASE_URL. Alamat
+// sengaja tidak dibentuk dari header Host request karena header tersebut dapat dipalsukan, sementara
+// URL ini dicetak pada dokumen resmi.
+func VerificationURL(code string) (string, error) {
+	baseURL := strings.TrimRight(strings.TrimSpace(os.Getenv("VERIFICATION_BASE_URL")), "/")
+	parsed, err := url.Parse(baseURL)
+	if baseURL == "" || err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", ErrVerificationBaseURLMissing
+	}
+	return baseURL + "/api/v1/verify/" + code, nil
+}
+
+func signVerificationPayload(secret []byte, payload []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("sppm-verification-v1:"))
+	mac.Write(payload)
+	return mac.Sum(nil)[:verificationSignatureLength]
+}